@@ -0,0 +1,59 @@
+package queries
+
+import (
+	"database/sql"
+	"database/sql/driver"
+)
+
+// ScanAs returns an [sql.Scanner] that scans the column as U and converts it to T, e.g. for an unusual encoding:
+//
+//	rows.Scan(queries.ScanAs(&ip, func(s string) (netip.Addr, error) { return netip.ParseAddr(s) }))
+//
+// U is converted from the driver value as in [sql.Rows.Scan]. NULL leaves dst unchanged.
+func ScanAs[T, U any](dst *T, convert func(U) (T, error)) sql.Scanner {
+	return scanAs[T, U]{dst: dst, convert: convert}
+}
+
+type scanAs[T, U any] struct {
+	dst     *T
+	convert func(U) (T, error)
+}
+
+// Scan implements the [sql.Scanner] interface.
+func (s scanAs[T, U]) Scan(src any) error {
+	var u sql.Null[U]
+	if err := u.Scan(src); err != nil {
+		return err
+	}
+	if !u.Valid {
+		return nil
+	}
+	t, err := s.convert(u.V)
+	if err != nil {
+		return err
+	}
+	*s.dst = t
+	return nil
+}
+
+// ValueAs returns a [driver.Valuer] that converts the value to U, the type stored in the database:
+//
+//	db.ExecContext(ctx, query, queries.ValueAs(ip, func(ip netip.Addr) (string, error) { return ip.String(), nil }))
+func ValueAs[T, U any](v T, convert func(T) (U, error)) driver.Valuer {
+	return valueAs[T, U]{v: v, convert: convert}
+}
+
+type valueAs[T, U any] struct {
+	v       T
+	convert func(T) (U, error)
+}
+
+// Value implements the [driver.Valuer] interface.
+func (v valueAs[T, U]) Value() (driver.Value, error) {
+	u, err := v.convert(v.v)
+	if err != nil {
+		return nil, err
+	}
+	// e.g. int must be converted to int64 to be a valid driver.Value.
+	return driver.DefaultParameterConverter.ConvertValue(u)
+}
@@ -0,0 +1,40 @@
+package queries_test
+
+import (
+	"database/sql/driver"
+	"net/netip"
+	"strconv"
+	"testing"
+
+	"go-simpler.org/queries"
+	"go-simpler.org/queries/internal/assert"
+	. "go-simpler.org/queries/internal/assert/EF"
+)
+
+func TestScanAs(t *testing.T) {
+	parse := func(s string) (netip.Addr, error) { return netip.ParseAddr(s) }
+
+	addr := netip.MustParseAddr("127.0.0.1")
+	err := queries.ScanAs(&addr, parse).Scan([]byte("::1"))
+	assert.NoErr[F](t, err)
+	assert.Equal[E](t, addr, netip.MustParseAddr("::1"))
+
+	err = queries.ScanAs(&addr, parse).Scan(nil)
+	assert.NoErr[F](t, err)
+	assert.Equal[E](t, addr, netip.MustParseAddr("::1"))
+
+	err = queries.ScanAs(&addr, parse).Scan("foo")
+	assert.Equal[E](t, err.Error(), `ParseAddr("foo"): unable to parse IP`)
+}
+
+func TestValueAs(t *testing.T) {
+	type level int
+
+	v, err := queries.ValueAs(level(3), func(l level) (int, error) { return int(l), nil }).Value()
+	assert.NoErr[F](t, err)
+	assert.Equal[E](t, v, driver.Value(int64(3)))
+
+	v, err = queries.ValueAs(level(3), func(l level) (string, error) { return strconv.Itoa(int(l)), nil }).Value()
+	assert.NoErr[F](t, err)
+	assert.Equal[E](t, v, driver.Value("3"))
+}
@@ -0,0 +1,116 @@
+package queries
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// BatchOptions are the options for [BatchedExec].
+type BatchOptions struct {
+	Pause      time.Duration                  // the delay between the batches, e.g. to let replicas catch up.
+	OnProgress func(batches int, total int64) // called after each batch with the number of batches and rows affected so far.
+}
+
+// BatchedExec executes the statement repeatedly until it affects fewer rows than batchSize, e.g. to delete old rows in small batches.
+// The batch size is passed to the statement as the last argument, so the statement must limit the rows with it:
+//
+//	-- MySQL:
+//	delete from events where created_at < ? limit ?
+//	-- PostgreSQL:
+//	delete from events where id in (select id from events where created_at < $1 limit $2)
+//	-- MSSQL:
+//	delete top (@p2) from events where created_at < @p1
+//
+// See [BatchedDelete] and [BatchedUpdate] for the helpers that bound the statement for the dialect.
+// It returns the total number of rows affected.
+func BatchedExec(ctx context.Context, e Execer, query string, batchSize int, opts BatchOptions, args ...any) (int64, error) {
+	if batchSize <= 0 {
+		panic("queries: batchSize must be positive")
+	}
+
+	args = append(args[:len(args):len(args)], batchSize)
+	return batchedExec(ctx, e, query, batchSize, opts, args)
+}
+
+// BatchedDelete deletes the rows of the table matching the condition in batches of up to batchSize rows using [BatchedExec].
+// The condition is a [Builder.Appendf] format for the dialect, e.g. "created_at < %P", and args are its arguments.
+// The statement is bounded per dialect:
+//
+//	-- MySQL:
+//	delete from events where created_at < ? limit ?
+//	-- PostgreSQL (and SQLite via rowid, since its DELETE ... LIMIT is a compile-time option):
+//	delete from events where ctid in (select ctid from events where created_at < $1 limit $2)
+//	-- MSSQL:
+//	delete top (@p1) from events where created_at < @p2
+//	-- Oracle:
+//	delete from events where (created_at < :1) and rownum <= :2
+//
+// It returns the total number of rows affected.
+func BatchedDelete(ctx context.Context, e Execer, d Dialect, table, where string, batchSize int, opts BatchOptions, args ...any) (int64, error) {
+	return batchedStatement(ctx, e, d, "delete from "+table, "delete top (%P) from "+table, table, where, batchSize, opts, args)
+}
+
+// BatchedUpdate updates the rows of the table matching the condition in batches of up to batchSize rows using [BatchedExec].
+// The assignments and the condition are [Builder.Appendf] formats for the dialect, e.g. "status = %P" and "created_at < %P",
+// and args are their arguments in order.
+// The statement is bounded per dialect the same way as in [BatchedDelete].
+// The condition must stop matching the updated rows, otherwise the same rows are updated again and again.
+// It returns the total number of rows affected.
+func BatchedUpdate(ctx context.Context, e Execer, d Dialect, table, set, where string, batchSize int, opts BatchOptions, args ...any) (int64, error) {
+	return batchedStatement(ctx, e, d, "update "+table+" set "+set, "update top (%P) "+table+" set "+set, table, where, batchSize, opts, args)
+}
+
+// batchedStatement bounds the statement to batchSize rows for the dialect and executes it with [BatchedExec].
+// MSSQL uses top, the same statement with a TOP clause for the batch size, instead of stmt.
+func batchedStatement(ctx context.Context, e Execer, d Dialect, stmt, top, table, where string, batchSize int, opts BatchOptions, args []any) (int64, error) {
+	if batchSize <= 0 {
+		panic("queries: batchSize must be positive")
+	}
+
+	qb := Builder{Dialect: d}
+	switch d {
+	case MySQL:
+		qb.Appendf(stmt+" where "+where+" limit %P", append(args[:len(args):len(args)], batchSize)...)
+	case PostgreSQL:
+		qb.Appendf(stmt+" where ctid in (select ctid from "+table+" where "+where+" limit %P)", append(args[:len(args):len(args)], batchSize)...)
+	case SQLite:
+		qb.Appendf(stmt+" where rowid in (select rowid from "+table+" where "+where+" limit %P)", append(args[:len(args):len(args)], batchSize)...)
+	case MSSQL:
+		qb.Appendf(top+" where "+where, append([]any{batchSize}, args...)...)
+	case Oracle:
+		qb.Appendf(stmt+" where ("+where+") and rownum <= %P", append(args[:len(args):len(args)], batchSize)...)
+	default:
+		panic(fmt.Sprintf("queries: unknown dialect %d", d))
+	}
+	return batchedExec(ctx, e, qb.String(), batchSize, opts, qb.Args)
+}
+
+// batchedExec executes the query with the args until it affects fewer rows than batchSize (see [BatchedExec]).
+func batchedExec(ctx context.Context, e Execer, query string, batchSize int, opts BatchOptions, args []any) (int64, error) {
+	var total int64
+	for batches := 1; ; batches++ {
+		res, err := Exec(ctx, e, query, args...)
+		if err != nil {
+			return total, err
+		}
+
+		total += res.RowsAffected
+		if opts.OnProgress != nil {
+			opts.OnProgress(batches, total)
+		}
+		if res.RowsAffected < int64(batchSize) {
+			return total, nil
+		}
+
+		if opts.Pause > 0 {
+			timer := time.NewTimer(opts.Pause)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return total, ctx.Err()
+			case <-timer.C:
+			}
+		}
+	}
+}
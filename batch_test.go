@@ -0,0 +1,116 @@
+package queries_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"go-simpler.org/queries"
+	"go-simpler.org/queries/internal/assert"
+	. "go-simpler.org/queries/internal/assert/EF"
+)
+
+func TestBatchedExec(t *testing.T) {
+	ctx := context.Background()
+	const query = "delete from events where created_at < ? limit ?"
+
+	t.Run("ok", func(t *testing.T) {
+		e := &batchExecer{rowsAffected: []int64{10, 10, 3}}
+
+		var progress []int64
+		opts := queries.BatchOptions{
+			OnProgress: func(_ int, total int64) { progress = append(progress, total) },
+		}
+
+		total, err := queries.BatchedExec(ctx, e, query, 10, opts, "2024-01-01")
+		assert.NoErr[F](t, err)
+		assert.Equal[E](t, total, int64(23))
+		assert.Equal[E](t, progress, []int64{10, 20, 23})
+		assert.Equal[E](t, e.args, [][]any{{"2024-01-01", 10}, {"2024-01-01", 10}, {"2024-01-01", 10}})
+	})
+
+	t.Run("error", func(t *testing.T) {
+		errDB := errors.New("database error")
+		e := &batchExecer{rowsAffected: []int64{10}, err: errDB}
+
+		total, err := queries.BatchedExec(ctx, e, query, 10, queries.BatchOptions{}, "2024-01-01")
+		assert.IsErr[E](t, err, errDB)
+		assert.Equal[E](t, total, int64(10))
+	})
+}
+
+func TestBatchedDelete(t *testing.T) {
+	ctx := context.Background()
+
+	tests := map[queries.Dialect]struct {
+		query string
+		args  []any
+	}{
+		queries.PostgreSQL: {"delete from events where ctid in (select ctid from events where created_at < $1 limit $2)", []any{"2024-01-01", 10}},
+		queries.MySQL:      {"delete from events where created_at < ? limit ?", []any{"2024-01-01", 10}},
+		queries.SQLite:     {"delete from events where rowid in (select rowid from events where created_at < ? limit ?)", []any{"2024-01-01", 10}},
+		queries.MSSQL:      {"delete top (@p1) from events where created_at < @p2", []any{10, "2024-01-01"}},
+		queries.Oracle:     {"delete from events where (created_at < :1) and rownum <= :2", []any{"2024-01-01", 10}},
+	}
+
+	for d, tt := range tests {
+		t.Run(d.String(), func(t *testing.T) {
+			e := &batchExecer{rowsAffected: []int64{10, 3}}
+			total, err := queries.BatchedDelete(ctx, e, d, "events", "created_at < %P", 10, queries.BatchOptions{}, "2024-01-01")
+			assert.NoErr[F](t, err)
+			assert.Equal[E](t, total, int64(13))
+			assert.Equal[E](t, e.queries, []string{tt.query, tt.query})
+			assert.Equal[E](t, e.args, [][]any{tt.args, tt.args})
+		})
+	}
+
+	assert.Panics[E](t, func() {
+		_, _ = queries.BatchedDelete(ctx, &batchExecer{}, 0, "events", "true", 10, queries.BatchOptions{})
+	}, "queries: unknown dialect 0")
+}
+
+func TestBatchedUpdate(t *testing.T) {
+	ctx := context.Background()
+
+	tests := map[queries.Dialect]struct {
+		query string
+		args  []any
+	}{
+		queries.PostgreSQL: {"update events set status = $1 where ctid in (select ctid from events where status = $2 limit $3)", []any{"archived", "new", 10}},
+		queries.MySQL:      {"update events set status = ? where status = ? limit ?", []any{"archived", "new", 10}},
+		queries.SQLite:     {"update events set status = ? where rowid in (select rowid from events where status = ? limit ?)", []any{"archived", "new", 10}},
+		queries.MSSQL:      {"update top (@p1) events set status = @p2 where status = @p3", []any{10, "archived", "new"}},
+		queries.Oracle:     {"update events set status = :1 where (status = :2) and rownum <= :3", []any{"archived", "new", 10}},
+	}
+
+	for d, tt := range tests {
+		t.Run(d.String(), func(t *testing.T) {
+			e := &batchExecer{rowsAffected: []int64{3}}
+			total, err := queries.BatchedUpdate(ctx, e, d, "events", "status = %P", "status = %P", 10, queries.BatchOptions{}, "archived", "new")
+			assert.NoErr[F](t, err)
+			assert.Equal[E](t, total, int64(3))
+			assert.Equal[E](t, e.queries, []string{tt.query})
+			assert.Equal[E](t, e.args, [][]any{tt.args})
+		})
+	}
+}
+
+// batchExecer returns the given numbers of rows affected one by one, then the error.
+type batchExecer struct {
+	rowsAffected []int64
+	err          error
+	queries      []string
+	args         [][]any
+}
+
+func (e *batchExecer) ExecContext(_ context.Context, query string, args ...any) (sql.Result, error) {
+	e.queries = append(e.queries, query)
+	e.args = append(e.args, args)
+	if len(e.rowsAffected) == 0 {
+		return nil, e.err
+	}
+	n := e.rowsAffected[0]
+	e.rowsAffected = e.rowsAffected[1:]
+	return fakeResult{rowsAffected: n}, nil
+}
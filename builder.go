@@ -1,52 +1,309 @@
 package queries
 
 import (
+	"bytes"
+	"cmp"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
 	"strings"
+	"time"
+	"unicode/utf8"
 )
 
 type Builder struct {
-	query       strings.Builder
+	query       bytes.Buffer
 	Args        []any
 	counter     int
 	placeholder rune
+	err         error // the first misuse of a placeholder verb.
+	whereEnd    int   // the length of the query after the last condition added by Where.
+	withEnd     int   // the length of the query after the last CTE added by With.
+
+	// Dialect is used by the helpers that render database-specific syntax.
+	// If not set, it is inferred from the placeholder verbs used (see [Builder.Appendf]).
+	Dialect Dialect
+
+	// NamedArgs makes the %@ verb add its arguments to [Builder.Args] as sql.Named("pN", value).
+	// Drivers that resolve @pN placeholders by name (e.g. go-mssqldb) then cannot misbind them.
+	NamedArgs bool
+
+	// EmptyAsNull makes the + flag render an empty slice as null (e.g. "in (null)", which matches no rows) instead of reporting a misuse.
+	// Note that "not in (null)" matches no rows either.
+	// Without it, the misuse can still be handled as an error via [Builder.Build].
+	EmptyAsNull bool
 }
 
+// Appendf formats according to the format specifier and appends the result to the query.
+// In addition to the standard [fmt] verbs, the following placeholder verbs are supported:
+//
+//   - %? for MySQL and SQLite (?)
+//   - %$ for PostgreSQL ($1, $2, ...)
+//   - %@ for MSSQL (@p1, @p2, ...)
+//   - %: for Oracle (:1, :2, ...)
+//   - %P for the placeholder of [Builder.Dialect], which must be set
+//
+// A *Builder argument formatted with %s, %v or a placeholder verb is embedded (e.g. as a subquery):
+// its arguments are added to [Builder.Args] and its placeholders are renumbered to follow the ones used before.
+// A [Sqlizer] argument formatted with %s, %v or a placeholder verb is embedded the same way, with its ? placeholders replaced.
+//
+// The %I verb quotes its argument, a string, as an identifier (e.g. a table or a column name) of the dialect.
+// A dotted name (e.g. "schema.table") is quoted part by part.
+// With the + flag, the argument must be a []string, which is rendered as a comma-separated list of identifiers.
+//
+// Using %P (%p is reserved by [fmt] for pointers) lets the same format strings work with any database by setting the dialect once.
+// The argument of a placeholder verb is added to [Builder.Args] instead of the query.
+// With the + flag (e.g. %+$), the argument must be a non-empty slice, which is expanded to a comma-separated list of placeholders.
+// A map (e.g. a set of ids) is expanded to its keys, which are sorted so that the query is the same across runs.
+// A slice of structs is expanded to row tuples, using the fields tagged with `sql` in declaration order,
+// e.g. "values (%+$)" with two rows becomes "values ($1, $2), ($3, $4)".
+//
+// An argument can be referenced several times with an explicit index (e.g. "%$ ... %[1]$").
+// For numbered placeholders, it is added to [Builder.Args] once and the same placeholder is rendered each time;
+// %? has no numbers, so the argument is added again.
 func (b *Builder) Appendf(format string, args ...any) {
+	bound := make(map[boundKey]string)
 	a := make([]any, len(args))
 	for i, arg := range args {
-		a[i] = argument{value: arg, builder: b}
+		a[i] = argument{value: arg, index: i, builder: b, bound: bound}
 	}
 	fmt.Fprintf(&b.query, format, a...)
 }
 
 func (b *Builder) String() string { return b.string() }
 
+// Err returns an error if the query is malformed, e.g. a %+$ argument is not a slice or different placeholders are used.
+// Unlike [Builder.String], which panics in this case, it lets the misuse be handled as a regular error.
+func (b *Builder) Err() error {
+	if b.err != nil {
+		return b.err
+	}
+	query := b.query.String()
+	if strings.Contains(query, "%!") {
+		return fmt.Errorf("queries: bad query: %s", query)
+	}
+	if b.placeholder == -1 {
+		return errors.New("queries: bad query: different placeholders used")
+	}
+	return nil
+}
+
+// Build returns the query and its arguments, or the error reported by [Builder.Err].
+func (b *Builder) Build() (string, []any, error) {
+	if err := b.Err(); err != nil {
+		return "", nil, err
+	}
+	return b.query.String(), b.Args, nil
+}
+
+// DebugString returns the query with the arguments inlined, e.g. to paste it into a database console.
+// The literals follow the dialect (e.g. backslashes are escaped for MySQL), which is inferred from the placeholders
+// if [Builder.Dialect] is not set.
+// It is for logging and debugging only: the arguments are quoted on a best-effort basis,
+// so the result must never be executed.
 func (b *Builder) DebugString() string {
 	query := b.string()
-	for i, arg := range b.Args {
-		var sarg string
-		switch arg := arg.(type) {
-		case string:
-			sarg = fmt.Sprintf("'%s'", arg)
-		case fmt.Stringer:
-			sarg = fmt.Sprintf("'%s'", arg.String())
-		default:
-			sarg = fmt.Sprintf("%v", arg)
-		}
-
+	if len(b.Args) == 0 {
+		return query
+	}
+	d := b.Dialect
+	if d == 0 {
 		switch b.placeholder {
-		case '?':
-			query = strings.Replace(query, "?", sarg, 1)
 		case '$':
-			query = strings.Replace(query, fmt.Sprintf("$%d", i+1), sarg, 1)
+			d = PostgreSQL
 		case '@':
-			query = strings.Replace(query, fmt.Sprintf("@p%d", i+1), sarg, 1)
-		default:
-			panic("unreachable")
+			d = MSSQL
+		case ':':
+			d = Oracle
 		}
 	}
-	return query
+	return replacePlaceholders(query, b.placeholder, func(n int) string {
+		if n > len(b.Args) {
+			return "?"
+		}
+		return debugValue(b.Args[n-1], d)
+	})
+}
+
+// debugValue returns the argument as an SQL literal of the dialect, which is 0 if unknown.
+func debugValue(arg any, d Dialect) string {
+	if named, ok := arg.(sql.NamedArg); ok {
+		arg = named.Value
+	}
+	if valuer, ok := arg.(driver.Valuer); ok {
+		if v, err := valuer.Value(); err == nil {
+			if p, ok := v.([]byte); ok {
+				return quoteString(string(p), d) // e.g. JSON: the valuers return text as []byte too.
+			}
+			arg = v
+		}
+	}
+
+	switch arg := arg.(type) {
+	case nil:
+		return "null"
+	case string:
+		return quoteString(arg, d)
+	case []byte:
+		return quoteBytes(arg, d)
+	case time.Time:
+		return quoteString(arg.Format("2006-01-02 15:04:05.999999999Z07:00"), d)
+	case fmt.Stringer:
+		return quoteString(arg.String(), d)
+	default:
+		return fmt.Sprintf("%v", arg)
+	}
+}
+
+// quoteString returns the string as an SQL literal of the dialect, escaping the single quotes inside it
+// (and the backslashes for MySQL, which treats them as escape characters by default).
+func quoteString(s string, d Dialect) string {
+	if d == MySQL {
+		s = strings.ReplaceAll(s, `\`, `\\`)
+	}
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// quoteBytes returns the bytes as a binary SQL literal of the dialect.
+func quoteBytes(p []byte, d Dialect) string {
+	switch d {
+	case PostgreSQL:
+		return `'\x` + hex.EncodeToString(p) + "'"
+	case MSSQL:
+		return "0x" + hex.EncodeToString(p)
+	case Oracle:
+		return "hextoraw('" + hex.EncodeToString(p) + "')"
+	default:
+		return "X'" + hex.EncodeToString(p) + "'"
+	}
+}
+
+// Sqlizer is implemented by query builders that render a query with ? placeholders and its arguments,
+// e.g. the ones of github.com/Masterminds/squirrel.
+type Sqlizer interface {
+	ToSql() (string, []any, error)
+}
+
+// ToSql implements the [Sqlizer] interface, so that the builder can be used where a Sqlizer is expected.
+// It is the same as [Builder.Build].
+func (b *Builder) ToSql() (string, []any, error) { return b.Build() }
+
+// Reset clears the query and its arguments so that the builder can be reused (e.g. with a [sync.Pool]).
+// The options (Dialect, NamedArgs, EmptyAsNull) are kept.
+// The memory of the query and [Builder.Args] is reused, so the slice returned by [Builder.Build] must not be used after Reset.
+func (b *Builder) Reset() {
+	b.query.Reset()
+	clear(b.Args) // do not retain the values.
+	b.Args = b.Args[:0]
+	b.counter = 0
+	b.placeholder = 0
+	b.err = nil
+	b.whereEnd = 0
+	b.withEnd = 0
+}
+
+// Mark is a snapshot of the [Builder] state returned by [Builder.Mark].
+type Mark struct {
+	query       int
+	args        int
+	counter     int
+	placeholder rune
+	err         error
+	whereEnd    int
+	withEnd     int
+}
+
+// Mark returns a snapshot of the builder state, which can be restored with [Builder.ResetTo]
+// to roll back the clauses appended after it, e.g. an optional filter that turned out to be unneeded.
+func (b *Builder) Mark() Mark {
+	return Mark{
+		query:       b.query.Len(),
+		args:        len(b.Args),
+		counter:     b.counter,
+		placeholder: b.placeholder,
+		err:         b.err,
+		whereEnd:    b.whereEnd,
+		withEnd:     b.withEnd,
+	}
+}
+
+// ResetTo restores the builder state saved by [Builder.Mark], discarding everything appended after it.
+// It panics if the builder has been reset since the mark was taken.
+func (b *Builder) ResetTo(m Mark) {
+	if m.query > b.query.Len() || m.args > len(b.Args) {
+		panic("queries: the mark is ahead of the builder")
+	}
+	b.query.Truncate(m.query)
+	clear(b.Args[m.args:]) // do not retain the values.
+	b.Args = b.Args[:m.args]
+	b.counter = m.counter
+	b.placeholder = m.placeholder
+	b.err = m.err
+	b.whereEnd = m.whereEnd
+	b.withEnd = m.withEnd
+}
+
+// Grow grows the capacity of the query by n bytes and the capacity of [Builder.Args] by args elements.
+func (b *Builder) Grow(n, args int) {
+	b.query.Grow(n)
+	b.Args = slices.Grow(b.Args, args)
+}
+
+// dialect returns the dialect set explicitly or the one inferred from the placeholder verbs used.
+func (b *Builder) dialect() Dialect {
+	if b.Dialect != 0 {
+		return b.Dialect
+	}
+	switch b.placeholder {
+	case '$':
+		return PostgreSQL
+	case '@':
+		return MSSQL
+	case ':':
+		return Oracle
+	case '?':
+		panic("queries: %? is used by both MySQL and SQLite, set Builder.Dialect explicitly")
+	default:
+		panic("queries: unknown dialect, set Builder.Dialect explicitly")
+	}
+}
+
+// verb returns the placeholder verb used so far or the one of the dialect if none has been used yet.
+func (b *Builder) verb() rune {
+	if b.placeholder > 0 {
+		return b.placeholder
+	}
+	if b.Dialect == 0 {
+		panic("queries: unknown dialect, set Builder.Dialect explicitly")
+	}
+	return dialectVerb(b.Dialect)
+}
+
+// dialectVerb returns the placeholder verb of the dialect.
+func dialectVerb(d Dialect) rune {
+	return d.Info().Placeholder
+}
+
+// clone returns a copy of the builder that can be appended to independently.
+func (b *Builder) clone() *Builder {
+	c := &Builder{
+		Args:        slices.Clone(b.Args),
+		counter:     b.counter,
+		placeholder: b.placeholder,
+		err:         b.err,
+		whereEnd:    b.whereEnd,
+		withEnd:     b.withEnd,
+		Dialect:     b.Dialect,
+		NamedArgs:   b.NamedArgs,
+		EmptyAsNull: b.EmptyAsNull,
+	}
+	c.query.WriteString(b.query.String())
+	return c
 }
 
 func (b *Builder) string() string {
@@ -65,33 +322,347 @@ func (b *Builder) string() string {
 
 type argument struct {
 	value   any
+	index   int // the index of the argument in the Appendf call.
 	builder *Builder
+	bound   map[boundKey]string // the placeholders already rendered in the Appendf call.
+}
+
+// boundKey identifies the rendering of an argument with a placeholder verb.
+type boundKey struct {
+	index int
+	verb  rune
+	plus  bool
 }
 
 // Format implements the [fmt.Formatter] interface.
 func (a argument) Format(s fmt.State, verb rune) {
+	defer func() {
+		if r := recover(); r != nil {
+			// fmt recovers the panic and writes it to the query, keep the original message for Builder.Err.
+			if a.builder.err == nil {
+				a.builder.err = errors.New(fmt.Sprint(r))
+			}
+			panic(r)
+		}
+	}()
+
+	if verb == 'P' {
+		if a.builder.Dialect == 0 {
+			panic("queries: %P requires Builder.Dialect to be set")
+		}
+		verb = dialectVerb(a.builder.Dialect)
+	}
+
+	// *Builder implements Sqlizer too, but is embedded with its placeholders renumbered.
+	if sub, ok := a.value.(*Builder); ok {
+		switch verb {
+		case '?', '$', '@', ':':
+			a.builder.use(verb)
+			fallthrough
+		case 's', 'v':
+			fmt.Fprint(s, a.builder.embed(sub))
+			return
+		}
+	}
+	if sqlizer, ok := a.value.(Sqlizer); ok {
+		switch verb {
+		case 's', 'v':
+			fmt.Fprint(s, a.builder.embedSqlizer(a.builder.verb(), sqlizer))
+			return
+		case '?', '$', '@', ':':
+			fmt.Fprint(s, a.builder.embedSqlizer(verb, sqlizer))
+			return
+		}
+	}
+
 	switch verb {
-	case '?', '$', '@':
-		a.builder.Args = append(a.builder.Args, a.value)
-		if a.builder.placeholder == 0 {
-			a.builder.placeholder = verb
+	case 'I':
+		if !s.Flag('+') {
+			fmt.Fprint(s, a.builder.ident(a.value))
+			return
+		}
+		idents, ok := a.value.([]string)
+		if !ok || len(idents) == 0 {
+			panic("queries: %+I argument must be a non-empty []string")
+		}
+		for i, ident := range idents {
+			if i > 0 {
+				fmt.Fprint(s, ", ")
+			}
+			fmt.Fprint(s, a.builder.ident(ident))
+		}
+	case '?', '$', '@', ':':
+		key := boundKey{index: a.index, verb: verb, plus: s.Flag('+')}
+		p, ok := a.bound[key]
+		if !ok || verb == '?' {
+			p = a.builder.placeholders(verb, a.value, key.plus)
+			a.bound[key] = p
+		}
+		fmt.Fprint(s, p)
+	default:
+		format := fmt.FormatString(s, verb)
+		fmt.Fprintf(s, format, a.value)
+	}
+}
+
+// placeholders binds the value and returns its placeholder.
+// With expand, the value must be a slice or a map, whose elements (keys) are bound one by one.
+func (b *Builder) placeholders(verb rune, value any, expand bool) string {
+	if !expand {
+		return b.bind(verb, value)
+	}
+
+	// the + flag expands a slice into a comma-separated list of placeholders, e.g. for the IN clause.
+	v := reflect.ValueOf(value)
+	var values []reflect.Value
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			values = append(values, v.Index(i))
 		}
-		if a.builder.placeholder != verb {
-			a.builder.placeholder = -1
+	case reflect.Map:
+		values = sortedKeys(v)
+	default:
+		panic(fmt.Sprintf("queries: %%+%c argument must be a slice", verb))
+	}
+	if len(values) == 0 {
+		if b.EmptyAsNull {
+			return "null"
 		}
+		panic(fmt.Sprintf("queries: %%+%c argument must not be empty", verb))
 	}
 
+	var sb strings.Builder
+	for i, value := range values {
+		if value.Kind() == reflect.Pointer && isRow(value.Type().Elem()) {
+			value = value.Elem()
+		}
+		if !isRow(value.Type()) {
+			if i > 0 {
+				sb.WriteString(", ")
+			}
+			sb.WriteString(b.bind(verb, value.Interface()))
+			continue
+		}
+		// a struct is expanded to a row tuple, the parentheses around the first one and after the last one come from the format.
+		if i > 0 {
+			sb.WriteString("), (")
+		}
+		fields := parseStruct(value.Type())
+		if len(fields) == 0 {
+			panic(fmt.Sprintf("queries: %s has no fields tagged with `sql`", value.Type()))
+		}
+		for j, f := range fields {
+			if j > 0 {
+				sb.WriteString(", ")
+			}
+			sb.WriteString(b.bind(verb, f.value(value)))
+		}
+	}
+	return sb.String()
+}
+
+// isRow reports whether values of the type should be bound field by field as a row tuple.
+// Structs that can be bound directly (e.g. [time.Time] or the ones implementing [driver.Valuer]) are not included.
+func isRow(typ reflect.Type) bool {
+	return isStruct(typ) && !reflect.PointerTo(typ).Implements(valuerType)
+}
+
+var valuerType = reflect.TypeFor[driver.Valuer]()
+
+// ident validates the identifier and quotes it for the dialect.
+func (b *Builder) ident(value any) string {
+	name, ok := value.(string)
+	if !ok {
+		panic(fmt.Sprintf("queries: %%I argument must be a string, got %T", value))
+	}
+	if !utf8.ValidString(name) || strings.ContainsRune(name, 0) {
+		panic(fmt.Sprintf("queries: bad identifier %q", name))
+	}
+
+	d := b.dialect()
+	parts := strings.Split(name, ".")
+	for i, part := range parts {
+		if part == "" {
+			panic(fmt.Sprintf("queries: bad identifier %q", name))
+		}
+		parts[i] = quoteIdent(d, part)
+	}
+	return strings.Join(parts, ".")
+}
+
+// sortedKeys returns the keys of the map in ascending order.
+// The keys must be integers, floats or strings.
+func sortedKeys(m reflect.Value) []reflect.Value {
+	keys := m.MapKeys()
+	slices.SortFunc(keys, func(x, y reflect.Value) int {
+		switch x.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return cmp.Compare(x.Int(), y.Int())
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			return cmp.Compare(x.Uint(), y.Uint())
+		case reflect.Float32, reflect.Float64:
+			return cmp.Compare(x.Float(), y.Float())
+		case reflect.String:
+			return cmp.Compare(x.String(), y.String())
+		default:
+			panic(fmt.Sprintf("queries: map keys of type %s cannot be sorted", x.Type()))
+		}
+	})
+	return keys
+}
+
+// use records the placeholder verb, marking the placeholders as mixed if it differs from the ones used before or from the dialect.
+func (b *Builder) use(verb rune) {
+	if b.placeholder == 0 {
+		b.placeholder = verb
+		if b.Dialect != 0 && dialectVerb(b.Dialect) != verb {
+			b.placeholder = -1
+		}
+	}
+	if b.placeholder != verb {
+		b.placeholder = -1
+	}
+}
+
+// embed returns the query of the sub-builder with its placeholders renumbered to follow the ones of the builder,
+// and adds its arguments to the builder.
+func (b *Builder) embed(sub *Builder) string {
+	query := sub.string()
+	if len(sub.Args) == 0 {
+		return query
+	}
+
+	verb := sub.placeholder
+	b.use(verb)
+	if verb == '?' {
+		b.Args = append(b.Args, sub.Args...)
+		return query
+	}
+
+	offset := b.counter
+	for i, arg := range sub.Args {
+		if named, ok := arg.(sql.NamedArg); ok && verb == '@' {
+			arg = sql.Named(fmt.Sprintf("p%d", offset+i+1), named.Value)
+		}
+		b.Args = append(b.Args, arg)
+	}
+	b.counter += sub.counter
+
+	return replacePlaceholders(query, verb, func(n int) string {
+		return placeholder(verb, n+offset)
+	})
+}
+
+// replacePlaceholders replaces each placeholder of the verb in the query with the result of fn,
+// which is called with the number of the placeholder (the position for ?), skipping string literals.
+func replacePlaceholders(query string, verb rune, fn func(n int) string) string {
+	var prefix string
+	switch verb {
+	case '?':
+		prefix = "?"
+	case '$':
+		prefix = "$"
+	case '@':
+		prefix = "@p"
+	case ':':
+		prefix = ":"
+	default:
+		panic("unreachable")
+	}
+
+	var sb strings.Builder
+	var quoted bool
+	var count int
+	for i := 0; i < len(query); {
+		if query[i] == '\'' {
+			quoted = !quoted
+		}
+		if quoted || !strings.HasPrefix(query[i:], prefix) {
+			sb.WriteByte(query[i])
+			i++
+			continue
+		}
+		if verb == '?' {
+			count++
+			sb.WriteString(fn(count))
+			i++
+			continue
+		}
+		j := i + len(prefix)
+		for j < len(query) && query[j] >= '0' && query[j] <= '9' {
+			j++
+		}
+		n, err := strconv.Atoi(query[i+len(prefix) : j])
+		if err != nil { // not a placeholder.
+			sb.WriteString(query[i:j])
+			i = j
+			continue
+		}
+		sb.WriteString(fn(n))
+		i = j
+	}
+	return sb.String()
+}
+
+// embedSqlizer returns the query of the sqlizer with its ? placeholders replaced with the ones of the verb,
+// and adds its arguments to the builder.
+func (b *Builder) embedSqlizer(verb rune, sqlizer Sqlizer) string {
+	query, args, err := sqlizer.ToSql()
+	if err != nil {
+		panic(fmt.Sprintf("queries: %T.ToSql: %v", sqlizer, err))
+	}
+	query = replacePlaceholders(query, '?', func(n int) string {
+		if n > len(args) {
+			panic(fmt.Sprintf("queries: %T.ToSql returned %d arguments for more placeholders", sqlizer, len(args)))
+		}
+		return b.bind(verb, args[n-1])
+	})
+	return query
+}
+
+// placeholder returns the n-th placeholder of the verb.
+func placeholder(verb rune, n int) string {
+	switch verb {
+	case '?':
+		return "?"
+	case '$':
+		return fmt.Sprintf("$%d", n)
+	case '@':
+		return fmt.Sprintf("@p%d", n)
+	case ':':
+		return fmt.Sprintf(":%d", n)
+	default:
+		panic("unreachable")
+	}
+}
+
+// bind adds the value to the query arguments and returns the placeholder for it.
+func (b *Builder) bind(verb rune, value any) string {
+	b.use(verb)
+
 	switch verb {
 	case '?': // MySQL, SQLite
-		fmt.Fprint(s, "?")
+		b.Args = append(b.Args, value)
+		return "?"
 	case '$': // PostgreSQL
-		a.builder.counter++
-		fmt.Fprintf(s, "$%d", a.builder.counter)
+		b.Args = append(b.Args, value)
+		b.counter++
+		return fmt.Sprintf("$%d", b.counter)
 	case '@': // MSSQL
-		a.builder.counter++
-		fmt.Fprintf(s, "@p%d", a.builder.counter)
+		b.counter++
+		name := fmt.Sprintf("p%d", b.counter)
+		if b.NamedArgs {
+			b.Args = append(b.Args, sql.Named(name, value))
+		} else {
+			b.Args = append(b.Args, value)
+		}
+		return "@" + name
+	case ':': // Oracle
+		b.Args = append(b.Args, value)
+		b.counter++
+		return fmt.Sprintf(":%d", b.counter)
 	default:
-		format := fmt.FormatString(s, verb)
-		fmt.Fprintf(s, format, a.value)
+		panic("unreachable")
 	}
 }
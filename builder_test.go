@@ -2,6 +2,8 @@ package queries_test
 
 import (
 	"context"
+	"database/sql"
+	"database/sql/driver"
 	"testing"
 
 	"go-simpler.org/queries"
@@ -22,6 +24,45 @@ func TestBuilder(t *testing.T) {
 	assert.Equal[E](t, qb.Args, []any{1, 2, 3})
 }
 
+func TestBuilder_Build(t *testing.T) {
+	var qb queries.Builder
+	qb.Appendf("select * from tbl where foo = %$", 1)
+
+	query, args, err := qb.Build()
+	assert.NoErr[F](t, err)
+	assert.Equal[E](t, query, "select * from tbl where foo = $1")
+	assert.Equal[E](t, args, []any{1})
+}
+
+func TestBuilder_Reset(t *testing.T) {
+	qb := queries.Builder{Dialect: queries.PostgreSQL}
+	qb.Grow(64, 2)
+	qb.Appendf("select * from tbl where foo = %?", 1)
+	qb.Where("bar = %P", 2)
+
+	qb.Reset()
+	qb.Appendf("select * from tbl")
+	qb.Where("foo = %P", 3)
+	assert.Equal[E](t, qb.String(), "select * from tbl where foo = $1")
+	assert.Equal[E](t, qb.Args, []any{3})
+}
+
+func TestBuilder_ResetTo(t *testing.T) {
+	qb := queries.Builder{Dialect: queries.PostgreSQL}
+	qb.Appendf("select * from tbl")
+	qb.Where("foo = %P", 1)
+
+	m := qb.Mark()
+	qb.Where("bar = %P", 2)
+	qb.ResetTo(m)
+	qb.Where("baz = %P", 3)
+	assert.Equal[E](t, qb.String(), "select * from tbl where foo = $1 and baz = $2")
+	assert.Equal[E](t, qb.Args, []any{1, 3})
+
+	qb.Reset()
+	assert.Panics[E](t, func() { qb.ResetTo(m) }, "queries: the mark is ahead of the builder")
+}
+
 func TestBuilder_placeholders(t *testing.T) {
 	tests := map[string]struct {
 		format string
@@ -43,6 +84,11 @@ func TestBuilder_placeholders(t *testing.T) {
 			query:  "select * from tbl where foo = @p1 and bar = @p2 and baz = @p3",
 			debug:  "select * from tbl where foo = 42 and bar = 'test' and baz = 'context.Background'",
 		},
+		":": {
+			format: "select * from tbl where foo = %: and bar = %: and baz = %:",
+			query:  "select * from tbl where foo = :1 and bar = :2 and baz = :3",
+			debug:  "select * from tbl where foo = 42 and bar = 'test' and baz = 'context.Background'",
+		},
 	}
 
 	for name, tt := range tests {
@@ -56,10 +102,246 @@ func TestBuilder_placeholders(t *testing.T) {
 	}
 }
 
+func TestBuilder_DebugString(t *testing.T) {
+	var qb queries.Builder
+	qb.Appendf("select * from tbl where a in (%+$) and b = %$ and c = %$ and d = %$ and e = '$1'",
+		[]int{1, 2, 3, 4, 5, 6, 7, 8, 9}, "it's", nil, queries.JSON{V: map[string]int{"x": 1}})
+	assert.Equal[E](t, qb.DebugString(), `select * from tbl where a in (1, 2, 3, 4, 5, 6, 7, 8, 9) and b = 'it''s' and c = null and d = '{"x":1}' and e = '$1'`)
+}
+
+func TestBuilder_DebugString_dialect(t *testing.T) {
+	tests := map[queries.Dialect]string{
+		queries.PostgreSQL: `select * from tbl where a = 'C:\dir' and b = '\xcafe'`,
+		queries.MySQL:      `select * from tbl where a = 'C:\\dir' and b = X'cafe'`,
+		queries.SQLite:     `select * from tbl where a = 'C:\dir' and b = X'cafe'`,
+		queries.MSSQL:      `select * from tbl where a = 'C:\dir' and b = 0xcafe`,
+		queries.Oracle:     `select * from tbl where a = 'C:\dir' and b = hextoraw('cafe')`,
+	}
+
+	for d, want := range tests {
+		t.Run(d.String(), func(t *testing.T) {
+			qb := queries.Builder{Dialect: d}
+			qb.Appendf("select * from tbl where a = %P and b = %P", `C:\dir`, []byte{0xca, 0xfe})
+			assert.Equal[E](t, qb.DebugString(), want)
+		})
+	}
+}
+
+func TestBuilder_dialect(t *testing.T) {
+	tests := map[queries.Dialect]string{
+		queries.PostgreSQL: "select * from tbl where foo = $1 and bar in ($2, $3)",
+		queries.MySQL:      "select * from tbl where foo = ? and bar in (?, ?)",
+		queries.SQLite:     "select * from tbl where foo = ? and bar in (?, ?)",
+		queries.MSSQL:      "select * from tbl where foo = @p1 and bar in (@p2, @p3)",
+		queries.Oracle:     "select * from tbl where foo = :1 and bar in (:2, :3)",
+	}
+
+	for dialect, query := range tests {
+		t.Run(dialect.String(), func(t *testing.T) {
+			qb := queries.Builder{Dialect: dialect}
+			qb.Appendf("select * from tbl where foo = %P and bar in (%+P)", 1, []int{2, 3})
+			assert.Equal[E](t, qb.String(), query)
+			assert.Equal[E](t, qb.Args, []any{1, 2, 3})
+		})
+	}
+}
+
+func TestBuilder_sliceExpansion(t *testing.T) {
+	tests := map[string]struct {
+		format string
+		query  string
+	}{
+		"?": {
+			format: "select * from tbl where foo in (%+?) and bar = %?",
+			query:  "select * from tbl where foo in (?, ?, ?) and bar = ?",
+		},
+		"$": {
+			format: "select * from tbl where foo in (%+$) and bar = %$",
+			query:  "select * from tbl where foo in ($1, $2, $3) and bar = $4",
+		},
+		"@": {
+			format: "select * from tbl where foo in (%+@) and bar = %@",
+			query:  "select * from tbl where foo in (@p1, @p2, @p3) and bar = @p4",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var qb queries.Builder
+			qb.Appendf(tt.format, []int{1, 2, 3}, 4)
+			assert.Equal[E](t, qb.String(), tt.query)
+			assert.Equal[E](t, qb.Args, []any{1, 2, 3, 4})
+		})
+	}
+}
+
+func TestBuilder_argumentReuse(t *testing.T) {
+	tests := map[string]struct {
+		format string
+		query  string
+		args   []any
+	}{
+		"?": {
+			format: "select * from tbl where foo = %? or bar = %[1]? and baz in (%+?)",
+			query:  "select * from tbl where foo = ? or bar = ? and baz in (?, ?)",
+			args:   []any{1, 1, 2, 3},
+		},
+		"$": {
+			format: "select * from tbl where foo = %$ or bar = %[1]$ and baz in (%+$) and qux in (%+[2]$)",
+			query:  "select * from tbl where foo = $1 or bar = $1 and baz in ($2, $3) and qux in ($2, $3)",
+			args:   []any{1, 2, 3},
+		},
+		"@": {
+			format: "select * from tbl where foo = %@ or bar = %[1]@ and baz in (%+@)",
+			query:  "select * from tbl where foo = @p1 or bar = @p1 and baz in (@p2, @p3)",
+			args:   []any{1, 2, 3},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var qb queries.Builder
+			qb.Appendf(tt.format, 1, []int{2, 3})
+			assert.Equal[E](t, qb.String(), tt.query)
+			assert.Equal[E](t, qb.Args, tt.args)
+		})
+	}
+}
+
+func TestBuilder_structSliceExpansion(t *testing.T) {
+	users := []user{{ID: 1, Name: "Alice"}, {ID: 2, Name: "Bob"}}
+
+	var qb queries.Builder
+	qb.Appendf("insert into users (id, name) values (%+$)", users)
+	assert.Equal[E](t, qb.String(), "insert into users (id, name) values ($1, $2), ($3, $4)")
+	assert.Equal[E](t, qb.Args, []any{1, "Alice", 2, "Bob"})
+
+	qb.Reset()
+	qb.Appendf("insert into users (id, name) values (%+$)", []*user{&users[0]})
+	assert.Equal[E](t, qb.String(), "insert into users (id, name) values ($1, $2)")
+
+	qb.Reset()
+	qb.Appendf("select * from products where price in (%+$)", []money{{Cents: 100}, {Cents: 200}})
+	assert.Equal[E](t, qb.String(), "select * from products where price in ($1, $2)")
+	assert.Equal[E](t, qb.Args, []any{money{Cents: 100}, money{Cents: 200}})
+
+}
+
+// money is a struct bound as a single value.
+type money struct{ Cents int64 }
+
+func (m money) Value() (driver.Value, error) { return m.Cents, nil }
+
+func TestBuilder_mapExpansion(t *testing.T) {
+	var qb queries.Builder
+	qb.Appendf("select * from tbl where foo in (%+$) and bar in (%+$)",
+		map[int]struct{}{3: {}, 1: {}, 2: {}},
+		map[string]bool{"c": true, "a": true, "b": false},
+	)
+	assert.Equal[E](t, qb.String(), "select * from tbl where foo in ($1, $2, $3) and bar in ($4, $5, $6)")
+	assert.Equal[E](t, qb.Args, []any{1, 2, 3, "a", "b", "c"})
+}
+
+func TestBuilder_EmptyAsNull(t *testing.T) {
+	qb := queries.Builder{EmptyAsNull: true}
+	qb.Appendf("select * from tbl where foo in (%+$) and bar = %$", []int{}, 1)
+	assert.Equal[E](t, qb.String(), "select * from tbl where foo in (null) and bar = $1")
+	assert.Equal[E](t, qb.Args, []any{1})
+}
+
+func TestBuilder_ident(t *testing.T) {
+	tests := map[queries.Dialect]string{
+		queries.PostgreSQL: `select "id", "na""me" from "public"."users" where "id" = $1`,
+		queries.MySQL:      "select `id`, `na\"me` from `public`.`users` where `id` = ?",
+		queries.MSSQL:      `select [id], [na"me] from [public].[users] where [id] = @p1`,
+	}
+
+	for dialect, query := range tests {
+		t.Run(dialect.String(), func(t *testing.T) {
+			qb := queries.Builder{Dialect: dialect}
+			qb.Appendf("select %+I from %I where %I = %P", []string{"id", `na"me`}, "public.users", "id", 1)
+			assert.Equal[E](t, qb.String(), query)
+		})
+	}
+}
+
+func TestBuilder_embed(t *testing.T) {
+	tests := map[string]struct {
+		dialect queries.Dialect
+		query   string
+	}{
+		"$": {queries.PostgreSQL, "select * from users where id = $1 and exists (select 1 from orders where user_id = users.id and status = $2 and note != '$1') and name = $3"},
+		"?": {queries.MySQL, "select * from users where id = ? and exists (select 1 from orders where user_id = users.id and status = ? and note != '$1') and name = ?"},
+		"@": {queries.MSSQL, "select * from users where id = @p1 and exists (select 1 from orders where user_id = users.id and status = @p2 and note != '$1') and name = @p3"},
+		":": {queries.Oracle, "select * from users where id = :1 and exists (select 1 from orders where user_id = users.id and status = :2 and note != '$1') and name = :3"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			sub := queries.Builder{Dialect: tt.dialect}
+			sub.Appendf("select 1 from orders where user_id = users.id and status = %P and note != '$1'", "paid")
+
+			qb := queries.Builder{Dialect: tt.dialect}
+			qb.Appendf("select * from users where id = %P and exists (%s) and name = %P", 1, &sub, "Alice")
+			assert.Equal[E](t, qb.String(), tt.query)
+			assert.Equal[E](t, qb.Args, []any{1, "paid", "Alice"})
+		})
+	}
+
+	t.Run("named args", func(t *testing.T) {
+		sub := queries.Builder{Dialect: queries.MSSQL, NamedArgs: true}
+		sub.Appendf("select id from orders where status = %P", "paid")
+
+		qb := queries.Builder{Dialect: queries.MSSQL, NamedArgs: true}
+		qb.Appendf("select * from users where id = %P and id in (%s)", 1, &sub)
+		assert.Equal[E](t, qb.String(), "select * from users where id = @p1 and id in (select id from orders where status = @p2)")
+		assert.Equal[E](t, qb.Args, []any{sql.Named("p1", 1), sql.Named("p2", "paid")})
+	})
+
+	t.Run("placeholder verb", func(t *testing.T) {
+		sub := queries.Builder{Dialect: queries.PostgreSQL}
+		sub.Appendf("(select id from orders where status = %P)", "paid")
+
+		qb := queries.Builder{Dialect: queries.PostgreSQL}
+		qb.Appendf("select * from users where id = %P and id in %P", 1, &sub)
+		query, args, err := qb.Build()
+		assert.NoErr[F](t, err)
+		assert.Equal[E](t, query, "select * from users where id = $1 and id in (select id from orders where status = $2)")
+		assert.Equal[E](t, args, []any{1, "paid"})
+
+		qb = queries.Builder{}
+		qb.Appendf("select * from users where id in %?", &sub)
+		_, _, err = qb.Build()
+		assert.Equal[E](t, err.Error(), "queries: bad query: different placeholders used")
+	})
+}
+
+func TestBuilder_Sqlizer(t *testing.T) {
+	sub := sqlizer{query: "select id from orders where status = ? and note != '?' and total > ?", args: []any{"paid", 100}}
+
+	qb := queries.Builder{Dialect: queries.PostgreSQL}
+	qb.Appendf("select * from users where id = %P and id in (%s)", 1, sub)
+	assert.Equal[E](t, qb.String(), "select * from users where id = $1 and id in (select id from orders where status = $2 and note != '?' and total > $3)")
+	assert.Equal[E](t, qb.Args, []any{1, "paid", 100})
+
+	query, args, err := qb.ToSql()
+	assert.NoErr[F](t, err)
+	assert.Equal[E](t, query, qb.String())
+	assert.Equal[E](t, args, qb.Args)
+}
+
+type sqlizer struct {
+	query string
+	args  []any
+}
+
+func (s sqlizer) ToSql() (string, []any, error) { return s.query, s.args, nil }
+
 func TestBuilder_badQuery(t *testing.T) {
 	tests := map[string]struct {
 		appends  func(*queries.Builder)
 		panicMsg string
+		errMsg   string // if empty, the same as panicMsg.
 	}{
 		"bad verb": {
 			appends: func(qb *queries.Builder) {
@@ -79,6 +361,58 @@ func TestBuilder_badQuery(t *testing.T) {
 			},
 			panicMsg: "queries: bad query: select foo from tbl%!(EXTRA queries.argument=bar)",
 		},
+		"non-slice argument": {
+			appends: func(qb *queries.Builder) {
+				qb.Appendf("select * from tbl where foo in (%+$)", 1)
+			},
+			panicMsg: "queries: bad query: select * from tbl where foo in (%!$(PANIC=Format method: queries: %+$ argument must be a slice))",
+			errMsg:   "queries: %+$ argument must be a slice",
+		},
+		"empty slice": {
+			appends: func(qb *queries.Builder) {
+				qb.Appendf("select * from tbl where foo in (%+$)", []int{})
+			},
+			panicMsg: "queries: bad query: select * from tbl where foo in (%!$(PANIC=Format method: queries: %+$ argument must not be empty))",
+			errMsg:   "queries: %+$ argument must not be empty",
+		},
+		"placeholder of another dialect": {
+			appends: func(qb *queries.Builder) {
+				qb.Dialect = queries.PostgreSQL
+				qb.Appendf("select * from tbl where foo = %?", 1)
+			},
+			panicMsg: "queries: bad query: different placeholders used",
+		},
+		"no dialect for %P": {
+			appends: func(qb *queries.Builder) {
+				qb.Appendf("select * from tbl where foo = %P", 1)
+			},
+			panicMsg: "queries: bad query: select * from tbl where foo = %!P(PANIC=Format method: queries: %P requires Builder.Dialect to be set)",
+			errMsg:   "queries: %P requires Builder.Dialect to be set",
+		},
+		"bad identifier": {
+			appends: func(qb *queries.Builder) {
+				qb.Dialect = queries.PostgreSQL
+				qb.Appendf("select * from %I", "public..users")
+			},
+			panicMsg: `queries: bad query: select * from %!I(PANIC=Format method: queries: bad identifier "public..users")`,
+			errMsg:   `queries: bad identifier "public..users"`,
+		},
+		"struct without tagged fields": {
+			appends: func(qb *queries.Builder) {
+				type untagged struct{ ID int }
+				qb.Appendf("insert into tbl values (%+$)", []untagged{{ID: 1}})
+			},
+			panicMsg: "queries: bad query: insert into tbl values (%!$(PANIC=Format method: queries: queries_test.untagged has no fields tagged with `sql`))",
+			errMsg:   "queries: queries_test.untagged has no fields tagged with `sql`",
+		},
+		"embedded builder with different placeholders": {
+			appends: func(qb *queries.Builder) {
+				var sub queries.Builder
+				sub.Appendf("select id from orders where status = %?", "paid")
+				qb.Appendf("select * from users where id = %$ and id in (%s)", 1, &sub)
+			},
+			panicMsg: "queries: bad query: different placeholders used",
+		},
 		"different placeholders": {
 			appends: func(qb *queries.Builder) {
 				qb.Appendf("select * from tbl where foo = %? and bar = %$ and baz = %@", 1, 2, 3)
@@ -92,6 +426,69 @@ func TestBuilder_badQuery(t *testing.T) {
 			var qb queries.Builder
 			tt.appends(&qb)
 			assert.Panics[E](t, func() { _ = qb.String() }, tt.panicMsg)
+
+			errMsg := tt.errMsg
+			if errMsg == "" {
+				errMsg = tt.panicMsg
+			}
+			_, _, err := qb.Build()
+			assert.Equal[E](t, err.Error(), errMsg)
+		})
+	}
+}
+
+func TestBuilder_AppendLock(t *testing.T) {
+	var qb queries.Builder
+	qb.Appendf("select * from jobs where status = %$ limit 1", "new")
+	qb.AppendLock(queries.ForUpdateSkipLocked)
+	assert.Equal[E](t, qb.String(), "select * from jobs where status = $1 limit 1 for update skip locked")
+
+	qb = queries.Builder{Dialect: queries.SQLite}
+	assert.Panics[E](t, func() { qb.AppendLock(queries.ForUpdate) }, "queries: SQLite does not support row locking clauses")
+
+	qb = queries.Builder{}
+	qb.Appendf("select * from jobs where status = %?", "new")
+	assert.Panics[E](t, func() { qb.AppendLock(queries.ForUpdate) }, "queries: %? is used by both MySQL and SQLite, set Builder.Dialect explicitly")
+}
+
+func TestBuilder_NamedArgs(t *testing.T) {
+	qb := queries.Builder{NamedArgs: true}
+	qb.Appendf("select * from tbl where foo = %@ and bar in (%+@)", 1, []string{"a", "b"})
+	assert.Equal[E](t, qb.String(), "select * from tbl where foo = @p1 and bar in (@p2, @p3)")
+	assert.Equal[E](t, qb.Args, []any{sql.Named("p1", 1), sql.Named("p2", "a"), sql.Named("p3", "b")})
+	assert.Equal[E](t, qb.DebugString(), "select * from tbl where foo = 1 and bar in ('a', 'b')")
+}
+
+func TestBuilder_AppendLimitOffset(t *testing.T) {
+	tests := map[string]struct {
+		dialect queries.Dialect
+		query   string
+		args    []any
+	}{
+		"postgres": {
+			dialect: queries.PostgreSQL,
+			query:   "select * from tbl order by id limit $1 offset $2",
+			args:    []any{10, 20},
+		},
+		"sqlite": {
+			dialect: queries.SQLite,
+			query:   "select * from tbl order by id limit ? offset ?",
+			args:    []any{10, 20},
+		},
+		"mssql": {
+			dialect: queries.MSSQL,
+			query:   "select * from tbl order by id offset @p1 rows fetch next @p2 rows only",
+			args:    []any{20, 10},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			qb := queries.Builder{Dialect: tt.dialect}
+			qb.Appendf("select * from tbl order by id")
+			qb.AppendLimitOffset(10, 20)
+			assert.Equal[E](t, qb.String(), tt.query)
+			assert.Equal[E](t, qb.Args, tt.args)
 		})
 	}
 }
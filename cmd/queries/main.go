@@ -0,0 +1,254 @@
+// Command queries generates code for the [go-simpler.org/queries] package.
+//
+// Usage:
+//
+//	queries gen -type=T1,T2 [-columns] [-output=file] [dir]
+//
+// The gen command generates the ScanColumns method for each of the given struct types,
+// implementing the [go-simpler.org/queries.ColumnScanner] interface, so that rows are scanned without reflection.
+// With -columns, it also generates a typed constant for each column (e.g. UserColumnID for the ID field of User)
+// and the Columns method returning all the columns in declaration order,
+// so that queries and ORDER BY allow-lists do not repeat column names as plain strings.
+// It is intended to be used with go:generate:
+//
+//	//go:generate go run go-simpler.org/queries/cmd/queries gen -type=User
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+func main() {
+	if err := run(os.Args[1:]); err != nil {
+		fmt.Fprintf(os.Stderr, "queries: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string) error {
+	if len(args) == 0 || args[0] != "gen" {
+		return errors.New("usage: queries gen -type=T1,T2 [-columns] [-output=file] [dir]")
+	}
+
+	fs := flag.NewFlagSet("gen", flag.ContinueOnError)
+	types := fs.String("type", "", "comma-separated list of struct type names")
+	columns := fs.Bool("columns", false, "generate column constants and the Columns method")
+	output := fs.String("output", "queries_gen.go", "output file name")
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+	if *types == "" {
+		return errors.New("-type must be specified")
+	}
+
+	dir := "."
+	if fs.NArg() > 0 {
+		dir = fs.Arg(0)
+	}
+
+	src, err := generate(dir, strings.Split(*types, ","), *columns)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(filepath.Join(dir, *output), src, 0o644)
+}
+
+type structType struct {
+	name   string
+	fields []field
+}
+
+type field struct {
+	name   string // Go name.
+	column string
+	json   bool
+	layout string
+}
+
+// generate parses the Go package in the directory and generates the code for the given types.
+func generate(dir string, typeNames []string, columns bool) ([]byte, error) {
+	pkgName, structs, err := parsePackage(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated by go-simpler.org/queries/cmd/queries. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&buf, "package %s\n\n", pkgName)
+
+	var selected []structType
+	var usesQueries bool
+	for _, name := range typeNames {
+		st, ok := structs[name]
+		if !ok {
+			return nil, fmt.Errorf("struct type %s not found", name)
+		}
+		for _, f := range st.fields {
+			usesQueries = usesQueries || f.json || f.layout != ""
+		}
+		selected = append(selected, st)
+	}
+
+	if usesQueries {
+		fmt.Fprintf(&buf, "import (\n\"fmt\"\n\n\"go-simpler.org/queries\"\n)\n")
+	} else {
+		fmt.Fprintf(&buf, "import \"fmt\"\n")
+	}
+
+	for _, st := range selected {
+		writeScanColumns(&buf, st)
+		if columns {
+			writeColumns(&buf, st)
+		}
+	}
+
+	return format.Source(buf.Bytes())
+}
+
+func writeScanColumns(buf *bytes.Buffer, st structType) {
+	fmt.Fprintf(buf, "\n// ScanColumns implements the queries.ColumnScanner interface.\n")
+	fmt.Fprintf(buf, "func (x *%s) ScanColumns(columns []string, scan func(...any) error) error {\n", st.name)
+	fmt.Fprintf(buf, "target := make([]any, len(columns))\n")
+	fmt.Fprintf(buf, "for i, column := range columns {\n")
+	fmt.Fprintf(buf, "switch column {\n")
+	for _, f := range st.fields {
+		fmt.Fprintf(buf, "case %q:\n", f.column)
+		switch {
+		case f.json:
+			fmt.Fprintf(buf, "target[i] = queries.JSON{V: &x.%s}\n", f.name)
+		case f.layout != "":
+			fmt.Fprintf(buf, "target[i] = queries.Time{T: &x.%s, Layout: %q}\n", f.name, f.layout)
+		default:
+			fmt.Fprintf(buf, "target[i] = &x.%s\n", f.name)
+		}
+	}
+	fmt.Fprintf(buf, "default:\n")
+	fmt.Fprintf(buf, "return fmt.Errorf(\"queries: no field for the %%#q column\", column)\n")
+	fmt.Fprintf(buf, "}\n")
+	fmt.Fprintf(buf, "}\n")
+	fmt.Fprintf(buf, "return scan(target...)\n")
+	fmt.Fprintf(buf, "}\n")
+}
+
+func writeColumns(buf *bytes.Buffer, st structType) {
+	fmt.Fprintf(buf, "\n// %sColumn is a column of the %s struct.\n", st.name, st.name)
+	fmt.Fprintf(buf, "type %sColumn string\n", st.name)
+	fmt.Fprintf(buf, "\n// The columns of the %s struct.\n", st.name)
+	fmt.Fprintf(buf, "const (\n")
+	for _, f := range st.fields {
+		fmt.Fprintf(buf, "%sColumn%s %sColumn = %q\n", st.name, f.name, st.name, f.column)
+	}
+	fmt.Fprintf(buf, ")\n")
+	fmt.Fprintf(buf, "\n// Columns returns the columns of the %s struct in declaration order.\n", st.name)
+	fmt.Fprintf(buf, "func (%s) Columns() []string {\n", st.name)
+	fmt.Fprintf(buf, "return []string{\n")
+	for _, f := range st.fields {
+		fmt.Fprintf(buf, "string(%sColumn%s),\n", st.name, f.name)
+	}
+	fmt.Fprintf(buf, "}\n")
+	fmt.Fprintf(buf, "}\n")
+}
+
+// parsePackage parses the non-test Go files in the directory and returns the package name and its struct types.
+func parsePackage(dir string) (string, map[string]structType, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return "", nil, err
+	}
+
+	var pkgName string
+	structs := make(map[string]structType)
+	fset := token.NewFileSet()
+
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+
+		file, err := parser.ParseFile(fset, filepath.Join(dir, name), nil, parser.SkipObjectResolution)
+		if err != nil {
+			return "", nil, err
+		}
+		pkgName = file.Name.Name
+
+		for _, decl := range file.Decls {
+			gd, ok := decl.(*ast.GenDecl)
+			if !ok || gd.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range gd.Specs {
+				ts := spec.(*ast.TypeSpec)
+				if st, ok := ts.Type.(*ast.StructType); ok {
+					fields, err := parseFields(st)
+					if err != nil {
+						return "", nil, fmt.Errorf("%s: %w", ts.Name.Name, err)
+					}
+					structs[ts.Name.Name] = structType{name: ts.Name.Name, fields: fields}
+				}
+			}
+		}
+	}
+
+	if pkgName == "" {
+		return "", nil, fmt.Errorf("no Go files in %s", dir)
+	}
+
+	return pkgName, structs, nil
+}
+
+func parseFields(st *ast.StructType) ([]field, error) {
+	var fields []field
+
+	for _, f := range st.Fields.List {
+		if f.Tag == nil {
+			continue
+		}
+		tag, err := strconv.Unquote(f.Tag.Value)
+		if err != nil {
+			return nil, err
+		}
+		tag, ok := reflect.StructTag(tag).Lookup("sql")
+		if !ok || tag == "-" {
+			continue
+		}
+		column, options, _ := strings.Cut(tag, ",")
+		var isJSON bool
+		// the layout may contain commas, so it must be the last option.
+		options, layout, _ := strings.Cut(options, "layout=")
+		for _, option := range strings.Split(options, ",") {
+			switch option {
+			case "":
+			case "json":
+				isJSON = true
+			case "omitzero", "inout", "pk", "version": // not used when scanning.
+			default:
+				return nil, fmt.Errorf("unknown `sql` tag option %q", option)
+			}
+		}
+		for _, name := range f.Names {
+			if !name.IsExported() {
+				continue
+			}
+			if column == "" {
+				return nil, fmt.Errorf("%s field has an empty `sql` tag", name.Name)
+			}
+			fields = append(fields, field{name: name.Name, column: column, json: isJSON, layout: layout})
+		}
+	}
+
+	return fields, nil
+}
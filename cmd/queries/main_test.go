@@ -0,0 +1,30 @@
+package main
+
+import (
+	"os"
+	"testing"
+
+	"go-simpler.org/queries/internal/assert"
+	. "go-simpler.org/queries/internal/assert/EF"
+)
+
+func TestGenerate(t *testing.T) {
+	tests := map[string]struct {
+		columns bool
+		golden  string
+	}{
+		"scan":    {false, "testdata/queries_gen.go.golden"},
+		"columns": {true, "testdata/queries_gen_columns.go.golden"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := generate("testdata", []string{"User"}, tt.columns)
+			assert.NoErr[F](t, err)
+
+			want, err := os.ReadFile(tt.golden)
+			assert.NoErr[F](t, err)
+			assert.Equal[E](t, string(got), string(want))
+		})
+	}
+}
@@ -0,0 +1,12 @@
+package models
+
+import "time"
+
+type User struct {
+	ID        int            `sql:"id"`
+	Name      string         `sql:"name"`
+	Settings  map[string]any `sql:"settings,json"`
+	Birthday  time.Time      `sql:"birthday,layout=2006-01-02"`
+	Password  string         `sql:"-"`
+	createdAt string         `sql:"created_at"`
+}
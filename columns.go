@@ -0,0 +1,59 @@
+package queries
+
+import (
+	"reflect"
+	"strings"
+)
+
+// ColumnsOption is an option for [Columns].
+type ColumnsOption func(*columnsOptions)
+
+type columnsOptions struct {
+	prefix  string
+	dialect Dialect
+}
+
+// WithPrefix adds the prefix (e.g. a table alias "u.") to each column.
+func WithPrefix(prefix string) ColumnsOption {
+	return func(o *columnsOptions) { o.prefix = prefix }
+}
+
+// WithQuote quotes each column as an identifier of the dialect.
+func WithQuote(d Dialect) ColumnsOption {
+	return func(o *columnsOptions) { o.dialect = d }
+}
+
+// Columns returns the columns of the struct T, i.e. the names from the `sql` tags in declaration order.
+// It is useful to build a SELECT statement that is guaranteed to match the struct:
+//
+//	qb.Appendf("select %s from users", strings.Join(queries.Columns[User](), ", "))
+func Columns[T any](opts ...ColumnsOption) []string {
+	typ := reflect.TypeFor[T]()
+	if typ.Kind() != reflect.Struct {
+		panic("queries: T must be a struct")
+	}
+
+	var o columnsOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	fields := parseStruct(typ)
+	columns := make([]string, len(fields))
+	for i, f := range fields {
+		column := f.column
+		if o.dialect != 0 {
+			column = quoteIdent(o.dialect, column)
+		}
+		columns[i] = o.prefix + column
+	}
+
+	return columns
+}
+
+// quoteIdent quotes the identifier for the dialect, escaping the quote characters inside it.
+func quoteIdent(d Dialect, ident string) string {
+	info := d.Info()
+	q := string(info.QuoteClose)
+	return string(info.QuoteOpen) + strings.ReplaceAll(ident, q, q+q) + q
+}
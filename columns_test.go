@@ -0,0 +1,24 @@
+package queries_test
+
+import (
+	"testing"
+
+	"go-simpler.org/queries"
+	"go-simpler.org/queries/internal/assert"
+	. "go-simpler.org/queries/internal/assert/EF"
+)
+
+func TestColumns(t *testing.T) {
+	type row struct {
+		ID      int    `sql:"id"`
+		Name    string `sql:"name"`
+		Comment string `sql:"-"`
+		Age     int
+	}
+
+	assert.Equal[E](t, queries.Columns[row](), []string{"id", "name"})
+	assert.Equal[E](t, queries.Columns[row](queries.WithPrefix("u.")), []string{"u.id", "u.name"})
+	assert.Equal[E](t, queries.Columns[row](queries.WithPrefix("u."), queries.WithQuote(queries.PostgreSQL)), []string{`u."id"`, `u."name"`})
+	assert.Equal[E](t, queries.Columns[row](queries.WithQuote(queries.MySQL)), []string{"`id`", "`name`"})
+	assert.Equal[E](t, queries.Columns[row](queries.WithQuote(queries.MSSQL)), []string{"[id]", "[name]"})
+}
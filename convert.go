@@ -0,0 +1,60 @@
+package queries
+
+import (
+	"context"
+	"database/sql"
+)
+
+// QueryExecer is an interface implemented by [sql.DB], [sql.Tx] and [sql.Conn].
+type QueryExecer interface {
+	Queryer
+	Execer
+}
+
+// ArgConverter wraps a [QueryExecer] and converts each query argument before passing it on.
+// It keeps call sites driver-agnostic when a driver needs special argument types,
+// e.g. pq.Array for slices with lib/pq or 1/0 instead of bool for Oracle:
+//
+//	db := queries.ArgConverter{QueryExecer: sqlDB, Convert: func(arg any) any {
+//		if s, ok := arg.([]string); ok {
+//			return pq.Array(s)
+//		}
+//		return arg
+//	}}
+//
+// It can be passed to the package helpers, such as [Query] and [Exec], in place of the wrapped value.
+type ArgConverter struct {
+	QueryExecer
+	Convert func(arg any) any
+}
+
+// QueryContext implements the [Queryer] interface.
+func (c ArgConverter) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	return c.QueryExecer.QueryContext(ctx, query, c.convert(args)...)
+}
+
+// ExecContext implements the [Execer] interface.
+func (c ArgConverter) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	return c.QueryExecer.ExecContext(ctx, query, c.convert(args)...)
+}
+
+func (c ArgConverter) convert(args []any) []any {
+	converted := make([]any, len(args))
+	for i, arg := range args {
+		converted[i] = c.Convert(arg)
+	}
+	return converted
+}
+
+// FoldCase wraps a [QueryExecer] to match the column names returned by its queries with the `sql` tags case-insensitively:
+// when scanning with [Query], [QueryOne], [QueryMaybe] and [QueryPair] (whose prefixes are matched case-insensitively too), a column that does not match any tag exactly is matched in lower case.
+// It lets lowercase tags work for both Oracle, which returns unquoted identifiers in upper case, and PostgreSQL,
+// while mixed-case quoted identifiers still match their exact tags.
+// Types implementing [ColumnScanner] get all the column names in lower case.
+// Since the helpers detect it by type, it must not be wrapped by other types (e.g. [ArgConverter]).
+type FoldCase struct{ QueryExecer }
+
+func isFoldCase(q Queryer) bool {
+	_, ok := q.(FoldCase)
+	return ok
+}
@@ -0,0 +1,47 @@
+package queries_test
+
+import (
+	"context"
+	"database/sql/driver"
+	"testing"
+
+	"go-simpler.org/queries"
+	"go-simpler.org/queries/internal/assert"
+	. "go-simpler.org/queries/internal/assert/EF"
+)
+
+func TestArgConverter(t *testing.T) {
+	ctx := context.Background()
+	fdb := fakeDB{}
+
+	db := queries.ArgConverter{QueryExecer: fdb.open(t), Convert: func(arg any) any {
+		if b, ok := arg.(bool); ok {
+			if b {
+				return 1
+			}
+			return 0
+		}
+		return arg
+	}}
+
+	_, err := queries.Exec(ctx, db, "update users set active = :1 where id = :2", true, 1)
+	assert.NoErr[F](t, err)
+	assert.Equal[E](t, fdb.args, [][]any{{int64(1), int64(1)}})
+}
+
+func TestFoldCase(t *testing.T) {
+	ctx := context.Background()
+
+	type row struct {
+		ID        int    `sql:"id"`
+		CamelName string `sql:"CamelName"`
+	}
+
+	fdb := fakeDB{columns: []string{"ID", "CamelName"}, rows: [][]driver.Value{{int64(1), "Alice"}}}
+	r, err := queries.QueryOne[row](ctx, queries.FoldCase{QueryExecer: fdb.open(t)}, "select id, \"CamelName\" from users")
+	assert.NoErr[F](t, err)
+	assert.Equal[E](t, r, row{ID: 1, CamelName: "Alice"})
+
+	_, err = queries.QueryOne[row](ctx, fdb.open(t), "select id, \"CamelName\" from users")
+	assert.Equal[E](t, err.Error(), "queries: no field for the `ID` column")
+}
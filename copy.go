@@ -0,0 +1,120 @@
+package queries
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"reflect"
+	"strings"
+)
+
+// CopyIn loads the rows into the PostgreSQL table with COPY ... FROM STDIN, which is much faster than INSERT for large data sets.
+// The columns are the fields of T tagged with `sql`.
+// It uses the protocol of the github.com/lib/pq driver (see pq.CopyIn): the statement is prepared within a transaction,
+// executed once per row and once more without arguments to flush the data. Other drivers (e.g. pgx) have their own APIs for COPY.
+// It returns the number of rows loaded.
+func CopyIn[T any](ctx context.Context, tx Preparer, table string, rows iter.Seq[T]) (int64, error) {
+	typ := reflect.TypeFor[T]()
+	if !isStruct(typ) {
+		panic("queries: T must be a struct")
+	}
+
+	b := Builder{Dialect: PostgreSQL}
+	fields := parseStruct(typ)
+	columns := make([]string, len(fields))
+	for i, f := range fields {
+		columns[i] = b.ident(f.column)
+	}
+	query := fmt.Sprintf("copy %s (%s) from stdin", b.ident(table), strings.Join(columns, ", "))
+
+	stmt, err := tx.PrepareContext(ctx, query)
+	if err != nil {
+		return 0, fmt.Errorf("preparing query %q: %w", query, err)
+	}
+	defer stmt.Close()
+
+	var n int64
+	args := make([]any, len(fields))
+	for row := range rows {
+		v := reflect.ValueOf(row)
+		for i, f := range fields {
+			args[i] = f.value(v)
+		}
+		if _, err := stmt.ExecContext(ctx, args...); err != nil {
+			return n, fmt.Errorf("copying row %d: %w", n, err)
+		}
+		n++
+	}
+
+	if _, err := stmt.ExecContext(ctx); err != nil {
+		return n, fmt.Errorf("flushing copied rows: %w", err)
+	}
+	return n, nil
+}
+
+// CopyOptions are the options for [CopyTable].
+type CopyOptions[C any] struct {
+	SrcDialect Dialect // the dialect of the source database.
+	DstDialect Dialect // the dialect of the destination database.
+	BatchSize  int     // the number of rows selected and inserted at once; 1000 if zero.
+
+	// Cursor is the keyset cursor (see [Builder.AppendKeyset]) to start after, e.g. the last one reported by OnProgress.
+	// If nil, the copying starts from the first row.
+	Cursor *C
+
+	// OnProgress is called after each batch with the number of rows copied so far and the cursor of the last copied row.
+	// The cursor can be persisted to resume the copying after a failure.
+	OnProgress func(copied int64, cursor *C)
+}
+
+// CopyTable copies the rows selected from src into the table in dst, e.g. to migrate data between database engines.
+// The rows are read in batches using keyset pagination on the columns of the cursor C (usually the primary key),
+// so the selectQuery must not have ORDER BY or LIMIT clauses and must have a WHERE clause, e.g. "select id, name from users where 1=1".
+// The rows are inserted with [InsertSeq], so the columns are the fields of T tagged with `sql`.
+// If the copying fails, the rows of the last batch may have been partially inserted,
+// so the destination should ignore duplicates (or be cleaned up) when resuming from the last reported cursor.
+// It returns the number of rows copied.
+func CopyTable[T, C any](ctx context.Context, src Queryer, dst Execer, selectQuery, table string, opts CopyOptions[C]) (int64, error) {
+	batchSize := opts.BatchSize
+	if batchSize == 0 {
+		batchSize = 1000
+	}
+
+	cursor := opts.Cursor
+	var copied int64
+	for {
+		qb := Builder{Dialect: opts.SrcDialect}
+		qb.Appendf("%s", selectQuery)
+		qb.AppendKeyset(cursor, false, batchSize)
+
+		var last T
+		var n int
+		page := func(yield func(T, error) bool) {
+			for t, err := range Query[T](ctx, src, qb.String(), qb.Args...) {
+				if err == nil {
+					last = t
+					n++
+				}
+				if !yield(t, err) {
+					return
+				}
+			}
+		}
+
+		if _, err := InsertSeq(ctx, dst, opts.DstDialect, table, page, batchSize); err != nil {
+			return copied, err
+		}
+		if n == 0 {
+			return copied, nil
+		}
+
+		copied += int64(n)
+		cursor = KeysetCursor[C](last)
+		if opts.OnProgress != nil {
+			opts.OnProgress(copied, cursor)
+		}
+		if n < batchSize {
+			return copied, nil
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package queries_test
+
+import (
+	"context"
+	"database/sql/driver"
+	"slices"
+	"testing"
+
+	"go-simpler.org/queries"
+	"go-simpler.org/queries/internal/assert"
+	. "go-simpler.org/queries/internal/assert/EF"
+)
+
+func TestCopyIn(t *testing.T) {
+	ctx := context.Background()
+	var fdb fakeDB
+
+	users := []user{{ID: 1, Name: "Alice"}, {ID: 2, Name: "Bob"}}
+	n, err := queries.CopyIn(ctx, fdb.open(t), "public.users", slices.Values(users))
+	assert.NoErr[F](t, err)
+	assert.Equal[E](t, n, int64(2))
+
+	const query = `copy "public"."users" ("id", "name") from stdin`
+	assert.Equal[E](t, fdb.queries, []string{query, query, query})
+	assert.Equal[E](t, fdb.args, [][]any{{int64(1), "Alice"}, {int64(2), "Bob"}, {}})
+}
+
+func TestCopyTable(t *testing.T) {
+	ctx := context.Background()
+	src := fakeDB{columns: []string{"id", "name"}, rows: [][]driver.Value{{int64(2), "Bob"}, {int64(3), "Carol"}}}
+	dst := fakeDB{result: fakeResult{rowsAffected: 2}}
+
+	type cursor struct {
+		ID int `sql:"id"`
+	}
+
+	var cursors []cursor
+	n, err := queries.CopyTable[user](ctx, src.open(t), dst.open(t), "select id, name from users where 1=1", "users", queries.CopyOptions[cursor]{
+		SrcDialect: queries.PostgreSQL,
+		DstDialect: queries.MySQL,
+		BatchSize:  10,
+		Cursor:     &cursor{ID: 1},
+		OnProgress: func(copied int64, c *cursor) {
+			assert.Equal[E](t, copied, int64(2))
+			cursors = append(cursors, *c)
+		},
+	})
+	assert.NoErr[F](t, err)
+	assert.Equal[E](t, n, int64(2))
+	assert.Equal[E](t, cursors, []cursor{{ID: 3}})
+
+	assert.Equal[E](t, src.queries, []string{"select id, name from users where 1=1 and (id) > ($1) order by id limit $2"})
+	assert.Equal[E](t, src.args, [][]any{{int64(1), int64(10)}})
+	assert.Equal[E](t, dst.queries, []string{"insert into users (id, name) values (?, ?), (?, ?)"})
+	assert.Equal[E](t, dst.args, [][]any{{int64(2), "Bob", int64(3), "Carol"}})
+}
@@ -0,0 +1,28 @@
+package queries
+
+import (
+	"context"
+	"fmt"
+)
+
+// Count executes "select count(*) from (query) t" and returns the number of rows the query yields.
+func Count(ctx context.Context, q Queryer, query string, args ...any) (int64, error) {
+	return QueryOne[int64](ctx, q, "select count(*) from ("+query+") t", args...)
+}
+
+// Exists reports whether the query yields at least one row.
+// The query is wrapped in an EXISTS expression of the dialect, so the database stops at the first matching row.
+func Exists(ctx context.Context, q Queryer, d Dialect, query string, args ...any) (bool, error) {
+	var wrapped string
+	switch d {
+	case PostgreSQL, MySQL, SQLite:
+		wrapped = "select exists (" + query + ")"
+	case MSSQL:
+		wrapped = "select case when exists (" + query + ") then 1 else 0 end"
+	case Oracle:
+		wrapped = "select case when exists (" + query + ") then 1 else 0 end from dual"
+	default:
+		panic(fmt.Sprintf("queries: unknown dialect %d", d))
+	}
+	return QueryOne[bool](ctx, q, wrapped, args...)
+}
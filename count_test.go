@@ -0,0 +1,46 @@
+package queries_test
+
+import (
+	"context"
+	"database/sql/driver"
+	"testing"
+
+	"go-simpler.org/queries"
+	"go-simpler.org/queries/internal/assert"
+	. "go-simpler.org/queries/internal/assert/EF"
+)
+
+func TestCount(t *testing.T) {
+	ctx := context.Background()
+	fdb := fakeDB{columns: []string{"count"}, rows: [][]driver.Value{{int64(2)}}}
+
+	n, err := queries.Count(ctx, fdb.open(t), "select id from users where name = $1", "Alice")
+	assert.NoErr[F](t, err)
+	assert.Equal[E](t, n, int64(2))
+	assert.Equal[E](t, fdb.queries, []string{"select count(*) from (select id from users where name = $1) t"})
+	assert.Equal[E](t, fdb.args, [][]any{{"Alice"}})
+}
+
+func TestExists(t *testing.T) {
+	ctx := context.Background()
+
+	tests := map[queries.Dialect]struct {
+		value driver.Value
+		query string
+	}{
+		queries.PostgreSQL: {true, "select exists (select 1 from users)"},
+		queries.MySQL:      {int64(1), "select exists (select 1 from users)"},
+		queries.MSSQL:      {int64(1), "select case when exists (select 1 from users) then 1 else 0 end"},
+		queries.Oracle:     {int64(1), "select case when exists (select 1 from users) then 1 else 0 end from dual"},
+	}
+
+	for d, tt := range tests {
+		t.Run(d.String(), func(t *testing.T) {
+			fdb := fakeDB{columns: []string{"exists"}, rows: [][]driver.Value{{tt.value}}}
+			ok, err := queries.Exists(ctx, fdb.open(t), d, "select 1 from users")
+			assert.NoErr[F](t, err)
+			assert.Equal[E](t, ok, true)
+			assert.Equal[E](t, fdb.queries, []string{tt.query})
+		})
+	}
+}
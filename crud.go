@@ -0,0 +1,145 @@
+package queries
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Get selects the row of T from the table by its primary key.
+// T must be a struct whose primary key fields have the `pk` tag option (e.g. `sql:"id,pk"`);
+// the values of a composite key are passed in the order of the fields.
+// If there is no such row, [sql.ErrNoRows] is returned.
+func Get[T any](ctx context.Context, q Queryer, d Dialect, table string, pk ...any) (T, error) {
+	return get[T](ctx, q, d, table, 0, pk)
+}
+
+// GetLocked is [Get] that locks the selected row with the mode (see [Builder.AppendLock]),
+// e.g. to claim a job inside a transaction.
+// It panics if the dialect does not support the mode.
+func GetLocked[T any](ctx context.Context, q Queryer, d Dialect, table string, mode LockMode, pk ...any) (T, error) {
+	return get[T](ctx, q, d, table, mode, pk)
+}
+
+// get selects the row of T by its primary key, locking it with the mode unless it is 0.
+func get[T any](ctx context.Context, q Queryer, d Dialect, table string, mode LockMode, pk []any) (T, error) {
+	fields := structFields[T]()
+
+	columns := make([]string, len(fields))
+	for i, f := range fields {
+		columns[i] = f.column
+	}
+
+	qb := Builder{Dialect: d}
+	qb.Appendf("select %s from %s", strings.Join(columns, ", "), table)
+	wherePK(&qb, fields, pk)
+	if mode != 0 {
+		qb.AppendLock(mode)
+	}
+
+	return QueryOne[T](ctx, q, qb.String(), qb.Args...)
+}
+
+// ErrStaleRow is returned by [Update] if the row has been changed (or deleted) since it was read,
+// i.e. its version no longer matches the one of the updated value.
+var ErrStaleRow = errors.New("queries: stale row")
+
+// Update updates the row in the table identified by the primary key fields of the row (see [Get]), setting all its other columns.
+// Fields with the `omitzero` tag option are skipped if they have the zero value.
+// A missing row is not an error, check [Result.RowsAffected] if needed.
+//
+// If T has an integer field with the `version` tag option (e.g. `sql:"version,version"`), optimistic locking is used:
+// the row is only updated if its version equals the one of the field, and the version is incremented,
+// both in the table and in the row, so the same row can be updated again.
+// If no row has been updated, [ErrStaleRow] is returned; the caller should then re-read the row and retry.
+func Update[T any](ctx context.Context, e Execer, d Dialect, table string, row *T) (Result, error) {
+	fields := structFields[T]()
+	v := reflect.ValueOf(row).Elem()
+
+	set := make(map[string]any)
+	var pk []any
+	var version *field
+	for _, f := range fields {
+		switch {
+		case f.pk:
+			pk = append(pk, f.value(v))
+		case f.version:
+			version = &f
+		case f.omitZero && v.Field(f.index).IsZero():
+		default:
+			set[f.column] = f.value(v)
+		}
+	}
+
+	qb := Builder{Dialect: d}
+	qb.Appendf("update %s", table)
+	switch {
+	case version == nil:
+		qb.AppendSet(set)
+	case len(set) == 0:
+		qb.Appendf(" set %s = %s + 1", version.column, version.column)
+	default:
+		qb.AppendSet(set)
+		qb.Appendf(", %s = %s + 1", version.column, version.column)
+	}
+	wherePK(&qb, fields, pk)
+	if version != nil {
+		qb.Where("%s = %P", version.column, version.value(v))
+	}
+
+	res, err := Exec(ctx, e, qb.String(), qb.Args...)
+	if err != nil {
+		return Result{}, err
+	}
+	if version != nil {
+		if res.RowsAffected == 0 {
+			return res, ErrStaleRow
+		}
+		if vf := v.Field(version.index); vf.CanInt() {
+			vf.SetInt(vf.Int() + 1)
+		} else {
+			vf.SetUint(vf.Uint() + 1)
+		}
+	}
+	return res, nil
+}
+
+// Delete deletes the row of T from the table by its primary key (see [Get]).
+// A missing row is not an error, check [Result.RowsAffected] if needed.
+func Delete[T any](ctx context.Context, e Execer, d Dialect, table string, pk ...any) (Result, error) {
+	qb := Builder{Dialect: d}
+	qb.Appendf("delete from %s", table)
+	wherePK(&qb, structFields[T](), pk)
+
+	return Exec(ctx, e, qb.String(), qb.Args...)
+}
+
+// wherePK appends the WHERE clause matching the primary key columns of the fields with the values.
+func wherePK(qb *Builder, fields []field, values []any) {
+	var columns []string
+	for _, f := range fields {
+		if f.pk {
+			columns = append(columns, f.column)
+		}
+	}
+	switch {
+	case len(columns) == 0:
+		panic("queries: T has no fields with the `pk` tag option")
+	case len(columns) != len(values):
+		panic(fmt.Sprintf("queries: the primary key has %d columns, got %d values", len(columns), len(values)))
+	}
+	for i, column := range columns {
+		qb.Where("%s = %P", column, values[i])
+	}
+}
+
+// structFields returns the fields of T, which must be a struct.
+func structFields[T any]() []field {
+	typ := reflect.TypeFor[T]()
+	if !isStruct(typ) {
+		panic("queries: T must be a struct")
+	}
+	return parseStruct(typ)
+}
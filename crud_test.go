@@ -0,0 +1,104 @@
+package queries_test
+
+import (
+	"context"
+	"database/sql/driver"
+	"testing"
+
+	"go-simpler.org/queries"
+	"go-simpler.org/queries/internal/assert"
+	. "go-simpler.org/queries/internal/assert/EF"
+)
+
+type account struct {
+	ID    int    `sql:"id,pk"`
+	Name  string `sql:"name"`
+	Email string `sql:"email,omitzero"`
+}
+
+func TestGet(t *testing.T) {
+	ctx := context.Background()
+	fdb := fakeDB{columns: []string{"id", "name", "email"}, rows: [][]driver.Value{{int64(1), "Alice", "alice@example.com"}}}
+
+	a, err := queries.Get[account](ctx, fdb.open(t), queries.PostgreSQL, "accounts", 1)
+	assert.NoErr[F](t, err)
+	assert.Equal[E](t, a, account{ID: 1, Name: "Alice", Email: "alice@example.com"})
+	assert.Equal[E](t, fdb.queries, []string{"select id, name, email from accounts where id = $1"})
+	assert.Equal[E](t, fdb.args, [][]any{{int64(1)}})
+
+	assert.Panics[E](t, func() { _, _ = queries.Get[account](ctx, fdb.open(t), queries.PostgreSQL, "accounts") },
+		"queries: the primary key has 1 columns, got 0 values")
+	assert.Panics[E](t, func() { _, _ = queries.Get[user](ctx, fdb.open(t), queries.PostgreSQL, "users", 1) },
+		"queries: T has no fields with the `pk` tag option")
+}
+
+func TestGetLocked(t *testing.T) {
+	ctx := context.Background()
+	fdb := fakeDB{columns: []string{"id", "name", "email"}, rows: [][]driver.Value{{int64(1), "Alice", "alice@example.com"}}}
+
+	_, err := queries.GetLocked[account](ctx, fdb.open(t), queries.MySQL, "accounts", queries.ForUpdate, 1)
+	assert.NoErr[F](t, err)
+	assert.Equal[E](t, fdb.queries, []string{"select id, name, email from accounts where id = ? for update"})
+
+	assert.Panics[E](t, func() {
+		_, _ = queries.GetLocked[account](ctx, fdb.open(t), queries.SQLite, "accounts", queries.ForUpdate, 1)
+	}, "queries: SQLite does not support row locking clauses")
+}
+
+func TestUpdate(t *testing.T) {
+	ctx := context.Background()
+	fdb := fakeDB{result: fakeResult{rowsAffected: 1}}
+
+	res, err := queries.Update(ctx, fdb.open(t), queries.PostgreSQL, "accounts", &account{ID: 1, Name: "Bob"})
+	assert.NoErr[F](t, err)
+	assert.Equal[E](t, res.RowsAffected, int64(1))
+	assert.Equal[E](t, fdb.queries, []string{"update accounts set name = $1 where id = $2"})
+	assert.Equal[E](t, fdb.args, [][]any{{"Bob", int64(1)}})
+}
+
+func TestUpdate_version(t *testing.T) {
+	ctx := context.Background()
+
+	type document struct {
+		ID      int    `sql:"id,pk"`
+		Title   string `sql:"title"`
+		Version int    `sql:"version,version"`
+	}
+
+	t.Run("updated", func(t *testing.T) {
+		fdb := fakeDB{result: fakeResult{rowsAffected: 1}}
+		doc := document{ID: 1, Title: "foo", Version: 3}
+		_, err := queries.Update(ctx, fdb.open(t), queries.PostgreSQL, "documents", &doc)
+		assert.NoErr[F](t, err)
+		assert.Equal[E](t, doc.Version, 4)
+
+		doc.Title = "bar"
+		_, err = queries.Update(ctx, fdb.open(t), queries.PostgreSQL, "documents", &doc)
+		assert.NoErr[F](t, err)
+		assert.Equal[E](t, doc.Version, 5)
+
+		assert.Equal[E](t, fdb.queries, []string{
+			"update documents set title = $1, version = version + 1 where id = $2 and version = $3",
+			"update documents set title = $1, version = version + 1 where id = $2 and version = $3",
+		})
+		assert.Equal[E](t, fdb.args, [][]any{{"foo", int64(1), int64(3)}, {"bar", int64(1), int64(4)}})
+	})
+
+	t.Run("stale", func(t *testing.T) {
+		fdb := fakeDB{result: fakeResult{rowsAffected: 0}}
+		doc := document{ID: 1, Title: "foo", Version: 3}
+		_, err := queries.Update(ctx, fdb.open(t), queries.PostgreSQL, "documents", &doc)
+		assert.IsErr[E](t, err, queries.ErrStaleRow)
+		assert.Equal[E](t, doc.Version, 3)
+	})
+}
+
+func TestDelete(t *testing.T) {
+	ctx := context.Background()
+	fdb := fakeDB{result: fakeResult{rowsAffected: 1}}
+
+	_, err := queries.Delete[account](ctx, fdb.open(t), queries.MySQL, "accounts", 1)
+	assert.NoErr[F](t, err)
+	assert.Equal[E](t, fdb.queries, []string{"delete from accounts where id = ?"})
+	assert.Equal[E](t, fdb.args, [][]any{{int64(1)}})
+}
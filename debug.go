@@ -0,0 +1,47 @@
+package queries
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+// DebugHandler returns an [http.Handler] that reports the connection pool statistics of the database
+// and the recent queries of the recorder (if not nil) as JSON, for quick production triage.
+// The arguments of the queries are included, so the handler must not be exposed publicly.
+func DebugHandler(db *sql.DB, recorder *Recorder) http.Handler {
+	type query struct {
+		Query    string        `json:"query"`
+		Args     []string      `json:"args,omitempty"`
+		Start    time.Time     `json:"start"`
+		Duration time.Duration `json:"duration"`
+		Error    string        `json:"error,omitempty"`
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		var report struct {
+			Pool   sql.DBStats `json:"pool"`
+			Recent []query     `json:"recent,omitempty"`
+		}
+
+		report.Pool = db.Stats()
+		if recorder != nil {
+			for _, rec := range recorder.Recent() {
+				q := query{Query: rec.Query, Start: rec.Start, Duration: rec.Duration}
+				for _, arg := range rec.Args {
+					q.Args = append(q.Args, debugValue(arg, 0)) // the dialect is unknown.
+				}
+				if rec.Err != nil {
+					q.Error = rec.Err.Error()
+				}
+				report.Recent = append(report.Recent, q)
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		_ = enc.Encode(report) // the client has gone away.
+	})
+}
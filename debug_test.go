@@ -0,0 +1,37 @@
+package queries_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go-simpler.org/queries"
+	"go-simpler.org/queries/internal/assert"
+	. "go-simpler.org/queries/internal/assert/EF"
+)
+
+func TestDebugHandler(t *testing.T) {
+	var fdb fakeDB
+	db := fdb.open(t)
+	r := queries.NewRecorder(db, 10)
+	_, _ = r.ExecContext(context.Background(), "delete from users where name = ?", "Alice")
+
+	w := httptest.NewRecorder()
+	queries.DebugHandler(db, r).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/debug/queries", nil))
+	assert.Equal[E](t, w.Header().Get("Content-Type"), "application/json")
+
+	var report struct {
+		Pool   map[string]any `json:"pool"`
+		Recent []struct {
+			Query string   `json:"query"`
+			Args  []string `json:"args"`
+		} `json:"recent"`
+	}
+	assert.NoErr[F](t, json.Unmarshal(w.Body.Bytes(), &report))
+	assert.Equal[E](t, report.Pool["OpenConnections"], any(float64(1)))
+	assert.Equal[E](t, len(report.Recent), 1)
+	assert.Equal[E](t, report.Recent[0].Query, "delete from users where name = ?")
+	assert.Equal[E](t, report.Recent[0].Args, []string{"'Alice'"})
+}
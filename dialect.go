@@ -0,0 +1,103 @@
+package queries
+
+import "fmt"
+
+// Dialect is an SQL dialect.
+// It is used by the [Builder] helpers that render database-specific syntax.
+type Dialect int
+
+const (
+	PostgreSQL Dialect = iota + 1
+	MySQL
+	SQLite
+	MSSQL
+	Oracle
+)
+
+// String implements the [fmt.Stringer] interface.
+func (d Dialect) String() string {
+	switch d {
+	case PostgreSQL:
+		return "PostgreSQL"
+	case MySQL:
+		return "MySQL"
+	case SQLite:
+		return "SQLite"
+	case MSSQL:
+		return "MSSQL"
+	case Oracle:
+		return "Oracle"
+	default:
+		return "unknown"
+	}
+}
+
+// DialectInfo describes the syntax and the limits of a [Dialect].
+// It can be used to write database-agnostic helpers on top of the [Builder].
+type DialectInfo struct {
+	Placeholder      rune // the [Builder.Appendf] placeholder verb: '$', '?', '@' or ':'.
+	MaxPlaceholders  int  // the maximum number of placeholders in a single query.
+	MaxInsertRows    int  // the maximum number of rows in a single multi-row INSERT, 0 if there is no limit.
+	Returning        bool // whether INSERT supports returning columns (RETURNING, or OUTPUT for MSSQL).
+	Upsert           bool // whether INSERT supports resolving conflicts (ON CONFLICT, or ON DUPLICATE KEY UPDATE for MySQL).
+	ReleaseSavepoint bool // whether savepoints can be released (all dialects support creating them).
+	OffsetFetch      bool // whether pagination uses OFFSET ... FETCH NEXT instead of LIMIT ... OFFSET.
+	QuoteOpen        byte // the opening identifier quote character.
+	QuoteClose       byte // the closing identifier quote character.
+}
+
+// Info returns the description of the dialect.
+// It panics if the dialect is unknown.
+func (d Dialect) Info() DialectInfo {
+	switch d {
+	case PostgreSQL:
+		return DialectInfo{
+			Placeholder:      '$',
+			MaxPlaceholders:  65535,
+			Returning:        true,
+			Upsert:           true,
+			ReleaseSavepoint: true,
+			QuoteOpen:        '"',
+			QuoteClose:       '"',
+		}
+	case MySQL:
+		return DialectInfo{
+			Placeholder:      '?',
+			MaxPlaceholders:  65535,
+			Upsert:           true,
+			ReleaseSavepoint: true,
+			QuoteOpen:        '`',
+			QuoteClose:       '`',
+		}
+	case SQLite:
+		return DialectInfo{
+			Placeholder:      '?',
+			MaxPlaceholders:  32766,
+			Returning:        true,
+			Upsert:           true,
+			ReleaseSavepoint: true,
+			QuoteOpen:        '"',
+			QuoteClose:       '"',
+		}
+	case MSSQL:
+		return DialectInfo{
+			Placeholder:     '@',
+			MaxPlaceholders: 2100,
+			MaxInsertRows:   1000,
+			Returning:       true,
+			OffsetFetch:     true,
+			QuoteOpen:       '[',
+			QuoteClose:      ']',
+		}
+	case Oracle:
+		return DialectInfo{
+			Placeholder:     ':',
+			MaxPlaceholders: 65535,
+			OffsetFetch:     true,
+			QuoteOpen:       '"',
+			QuoteClose:      '"',
+		}
+	default:
+		panic(fmt.Sprintf("queries: unknown dialect %d", d))
+	}
+}
@@ -0,0 +1,39 @@
+package queries_test
+
+import (
+	"testing"
+
+	"go-simpler.org/queries"
+	"go-simpler.org/queries/internal/assert"
+	. "go-simpler.org/queries/internal/assert/EF"
+)
+
+func TestDialect_Info(t *testing.T) {
+	tests := map[queries.Dialect]struct {
+		placeholder string
+		quoted      string
+	}{
+		queries.PostgreSQL: {"$1", `"public"."users"`},
+		queries.MySQL:      {"?", "`public`.`users`"},
+		queries.SQLite:     {"?", `"public"."users"`},
+		queries.MSSQL:      {"@p1", "[public].[users]"},
+		queries.Oracle:     {":1", `"public"."users"`},
+	}
+
+	for d, tt := range tests {
+		t.Run(d.String(), func(t *testing.T) {
+			info := d.Info()
+			assert.Equal[E](t, info.MaxPlaceholders > 0, true)
+
+			qb := queries.Builder{Dialect: d}
+			qb.Appendf("%"+string(info.Placeholder), 1)
+			assert.Equal[E](t, qb.String(), tt.placeholder)
+
+			qb = queries.Builder{Dialect: d}
+			qb.Appendf("%I", "public.users")
+			assert.Equal[E](t, qb.String(), tt.quoted)
+		})
+	}
+
+	assert.Panics[E](t, func() { _ = queries.Dialect(0).Info() }, "queries: unknown dialect 0")
+}
@@ -0,0 +1,170 @@
+package queries
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"time"
+)
+
+// DiffOption is an option for [DiffRows].
+type DiffOption func(*diffOptions)
+
+type diffOptions struct {
+	ignoreOrder bool
+	key         []string
+}
+
+// IgnoreOrder matches the rows regardless of their order.
+// A row matches an equal row only, so changed rows are reported as missing from both sides; use [MatchBy] to find them.
+func IgnoreOrder() DiffOption {
+	return func(o *diffOptions) { o.ignoreOrder = true }
+}
+
+// MatchBy matches the rows with the same values of the columns (e.g. the primary key) regardless of their order.
+// T must be a struct with the columns in its `sql` tags.
+func MatchBy(columns ...string) DiffOption {
+	return func(o *diffOptions) { o.key = columns }
+}
+
+// DiffReport is the result of [DiffRows].
+type DiffReport struct {
+	OnlyA   []int     // the indexes of the rows of a that have no match in b.
+	OnlyB   []int     // the indexes of the rows of b that have no match in a.
+	Changed []RowDiff // the matched rows that differ.
+}
+
+// RowDiff is a pair of matched rows that differ.
+type RowDiff struct {
+	A, B    int      // the indexes of the rows in a and b.
+	Columns []string // the columns that differ, if T is a struct.
+}
+
+// Equal reports whether there are no differences.
+func (r DiffReport) Equal() bool {
+	return len(r.OnlyA) == 0 && len(r.OnlyB) == 0 && len(r.Changed) == 0
+}
+
+// DiffRows compares two result sets, e.g. the ones read from the old and the new databases during a migration.
+// By default, the rows are matched by position.
+// If T is a struct, its fields tagged with `sql` are compared and the columns that differ are reported.
+func DiffRows[T any](a, b []T, opts ...DiffOption) DiffReport {
+	var o diffOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	typ := reflect.TypeFor[T]()
+	var fields []field
+	if isStruct(typ) {
+		fields = parseStruct(typ)
+	}
+
+	// diff returns the columns that differ (if T is a struct) and whether the rows are equal.
+	diff := func(x, y T) ([]string, bool) {
+		vx, vy := reflect.ValueOf(x), reflect.ValueOf(y)
+		if fields == nil {
+			return nil, equalValues(vx, vy)
+		}
+		var columns []string
+		for _, f := range fields {
+			if !equalValues(vx.Field(f.index), vy.Field(f.index)) {
+				columns = append(columns, f.column)
+			}
+		}
+		return columns, len(columns) == 0
+	}
+
+	var r DiffReport
+
+	switch {
+	case len(o.key) > 0:
+		if fields == nil {
+			panic("queries: MatchBy requires T to be a struct")
+		}
+		var key []field
+		for _, column := range o.key {
+			i := slices.IndexFunc(fields, func(f field) bool { return f.column == column })
+			if i == -1 {
+				panic(fmt.Sprintf("queries: no field for the %#q column", column))
+			}
+			key = append(key, fields[i])
+		}
+		keyOf := func(row T) string {
+			v := reflect.ValueOf(row)
+			values := make([]any, len(key))
+			for i, f := range key {
+				values[i] = v.Field(f.index).Interface()
+			}
+			return fmt.Sprintf("%#v", values)
+		}
+
+		indexes := make(map[string][]int) // the unmatched rows of b by key.
+		for j, row := range b {
+			k := keyOf(row)
+			indexes[k] = append(indexes[k], j)
+		}
+		for i, row := range a {
+			k := keyOf(row)
+			if len(indexes[k]) == 0 {
+				r.OnlyA = append(r.OnlyA, i)
+				continue
+			}
+			j := indexes[k][0]
+			indexes[k] = indexes[k][1:]
+			if columns, equal := diff(row, b[j]); !equal {
+				r.Changed = append(r.Changed, RowDiff{A: i, B: j, Columns: columns})
+			}
+		}
+		for _, js := range indexes {
+			r.OnlyB = append(r.OnlyB, js...)
+		}
+		slices.Sort(r.OnlyB)
+
+	case o.ignoreOrder:
+		matched := make([]bool, len(b))
+		for i, row := range a {
+			j := -1
+			for k := range b {
+				if _, equal := diff(row, b[k]); equal && !matched[k] {
+					j = k
+					break
+				}
+			}
+			if j == -1 {
+				r.OnlyA = append(r.OnlyA, i)
+				continue
+			}
+			matched[j] = true
+		}
+		for j := range b {
+			if !matched[j] {
+				r.OnlyB = append(r.OnlyB, j)
+			}
+		}
+
+	default:
+		n := min(len(a), len(b))
+		for i := 0; i < n; i++ {
+			if columns, equal := diff(a[i], b[i]); !equal {
+				r.Changed = append(r.Changed, RowDiff{A: i, B: i, Columns: columns})
+			}
+		}
+		for i := n; i < len(a); i++ {
+			r.OnlyA = append(r.OnlyA, i)
+		}
+		for j := n; j < len(b); j++ {
+			r.OnlyB = append(r.OnlyB, j)
+		}
+	}
+
+	return r
+}
+
+// equalValues reports whether the values are deeply equal, comparing [time.Time] values by the instant they represent.
+func equalValues(x, y reflect.Value) bool {
+	if x.Type() == timeType {
+		return x.Interface().(time.Time).Equal(y.Interface().(time.Time))
+	}
+	return reflect.DeepEqual(x.Interface(), y.Interface())
+}
@@ -0,0 +1,68 @@
+package queries_test
+
+import (
+	"testing"
+	"time"
+
+	"go-simpler.org/queries"
+	"go-simpler.org/queries/internal/assert"
+	. "go-simpler.org/queries/internal/assert/EF"
+)
+
+func TestDiffRows(t *testing.T) {
+	type row struct {
+		ID        int       `sql:"id"`
+		Name      string    `sql:"name"`
+		CreatedAt time.Time `sql:"created_at"`
+	}
+
+	now := time.Now()
+	a := []row{
+		{ID: 1, Name: "Alice", CreatedAt: now},
+		{ID: 2, Name: "Bob", CreatedAt: now},
+		{ID: 3, Name: "Carol", CreatedAt: now},
+	}
+	b := []row{
+		{ID: 2, Name: "Bob", CreatedAt: now.UTC()},
+		{ID: 1, Name: "Alicia", CreatedAt: now},
+		{ID: 4, Name: "Dave", CreatedAt: now},
+	}
+
+	tests := map[string]struct {
+		opts []queries.DiffOption
+		want queries.DiffReport
+	}{
+		"by position": {
+			want: queries.DiffReport{Changed: []queries.RowDiff{
+				{A: 0, B: 0, Columns: []string{"id", "name"}},
+				{A: 1, B: 1, Columns: []string{"id", "name"}},
+				{A: 2, B: 2, Columns: []string{"id", "name"}},
+			}},
+		},
+		"ignore order": {
+			opts: []queries.DiffOption{queries.IgnoreOrder()},
+			want: queries.DiffReport{OnlyA: []int{0, 2}, OnlyB: []int{1, 2}},
+		},
+		"match by key": {
+			opts: []queries.DiffOption{queries.MatchBy("id")},
+			want: queries.DiffReport{
+				OnlyA:   []int{2},
+				OnlyB:   []int{2},
+				Changed: []queries.RowDiff{{A: 0, B: 1, Columns: []string{"name"}}},
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			r := queries.DiffRows(a, b, tt.opts...)
+			assert.Equal[E](t, r, tt.want)
+			assert.Equal[E](t, r.Equal(), false)
+		})
+	}
+
+	t.Run("equal", func(t *testing.T) {
+		r := queries.DiffRows([]int{1, 2, 3}, []int{3, 2, 1}, queries.IgnoreOrder())
+		assert.Equal[E](t, r.Equal(), true)
+	})
+}
@@ -0,0 +1,131 @@
+package queries
+
+import (
+	"net"
+	"net/url"
+	"strconv"
+)
+
+// PostgresDSN is a connection string for PostgreSQL drivers (e.g. pgx, lib/pq).
+// The zero Port means the default one.
+type PostgresDSN struct {
+	Host     string
+	Port     int
+	User     string
+	Password string
+	Database string
+	Params   map[string]string // e.g. sslmode.
+}
+
+// String implements the [fmt.Stringer] interface.
+func (d PostgresDSN) String() string {
+	return dsnURL("postgres", d.Host, d.Port, d.User, d.Password, "/"+d.Database, d.Params)
+}
+
+// MySQLDSN is a connection string for the github.com/go-sql-driver/mysql driver.
+// The zero Port means the default one.
+type MySQLDSN struct {
+	Host     string
+	Port     int
+	User     string
+	Password string
+	Database string
+	Params   map[string]string // e.g. parseTime.
+}
+
+// String implements the [fmt.Stringer] interface.
+func (d MySQLDSN) String() string {
+	var s string
+	if d.User != "" {
+		s = d.User
+		if d.Password != "" {
+			s += ":" + d.Password
+		}
+		s += "@"
+	}
+	if d.Host != "" {
+		s += "tcp(" + hostPort(d.Host, d.Port) + ")"
+	}
+	s += "/" + url.PathEscape(d.Database)
+	if len(d.Params) > 0 {
+		s += "?" + encodeParams(d.Params)
+	}
+	return s
+}
+
+// MSSQLDSN is a connection string for the github.com/microsoft/go-mssqldb driver.
+// The zero Port means the default one.
+type MSSQLDSN struct {
+	Host     string
+	Port     int
+	Instance string
+	User     string
+	Password string
+	Database string
+	Params   map[string]string // e.g. encrypt.
+}
+
+// String implements the [fmt.Stringer] interface.
+func (d MSSQLDSN) String() string {
+	params := make(map[string]string, len(d.Params)+1)
+	for k, v := range d.Params {
+		params[k] = v
+	}
+	if d.Database != "" {
+		params["database"] = d.Database
+	}
+	var path string
+	if d.Instance != "" {
+		path = "/" + d.Instance
+	}
+	return dsnURL("sqlserver", d.Host, d.Port, d.User, d.Password, path, params)
+}
+
+// OracleDSN is a connection string for the github.com/sijms/go-ora driver.
+// The zero Port means the default one.
+type OracleDSN struct {
+	Host     string
+	Port     int
+	User     string
+	Password string
+	Service  string
+	Params   map[string]string // e.g. SSL.
+}
+
+// String implements the [fmt.Stringer] interface.
+func (d OracleDSN) String() string {
+	return dsnURL("oracle", d.Host, d.Port, d.User, d.Password, "/"+d.Service, d.Params)
+}
+
+// dsnURL returns a URL connection string with the user info and the parameters escaped.
+func dsnURL(scheme, host string, port int, user, password, path string, params map[string]string) string {
+	u := url.URL{
+		Scheme:   scheme,
+		Host:     hostPort(host, port),
+		Path:     path,
+		RawQuery: encodeParams(params),
+	}
+	switch {
+	case password != "":
+		u.User = url.UserPassword(user, password)
+	case user != "":
+		u.User = url.User(user)
+	}
+	return u.String()
+}
+
+func hostPort(host string, port int) string {
+	if port == 0 {
+		return host
+	}
+	return net.JoinHostPort(host, strconv.Itoa(port))
+}
+
+// encodeParams returns the parameters in the URL query format, sorted by key.
+func encodeParams(params map[string]string) string {
+	values := make(url.Values, len(params))
+	for k, v := range params {
+		values.Set(k, v)
+	}
+	return values.Encode()
+}
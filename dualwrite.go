@@ -0,0 +1,65 @@
+package queries
+
+import (
+	"context"
+	"database/sql"
+)
+
+// DualWriter is an [Execer] that applies the write statements to two targets,
+// e.g. the old and the new databases (or schemas) during a live migration.
+// The statements are executed on Primary first and then, if it succeeds, on Secondary.
+// Secondary is best-effort: its failure does not fail the statement, but is reported as a divergence.
+// Note that there is no distributed transaction, the targets must be reconciled separately (e.g. by a backfill).
+type DualWriter struct {
+	Primary   Execer
+	Secondary Execer
+
+	// OnDivergence is called when Secondary fails or affects a different number of rows than Primary.
+	// It must be safe for concurrent use if the DualWriter is.
+	OnDivergence func(ctx context.Context, d Divergence)
+}
+
+// Divergence describes a statement whose effect on the secondary target differs from the one on the primary target.
+type Divergence struct {
+	Query                 string
+	Args                  []any
+	Err                   error // the error of the secondary target, if any.
+	PrimaryRowsAffected   int64
+	SecondaryRowsAffected int64
+}
+
+// ExecContext implements the [Execer] interface.
+// It returns the result of Primary.
+func (w DualWriter) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	res, err := w.Primary.ExecContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	d := Divergence{Query: query, Args: args}
+
+	// not all drivers support it, in this case the number of rows is not compared.
+	primaryRows, primaryErr := res.RowsAffected()
+
+	secondaryRes, err := w.Secondary.ExecContext(ctx, query, args...)
+	if err != nil {
+		d.Err = err
+		w.report(ctx, d)
+		return res, nil
+	}
+
+	secondaryRows, secondaryErr := secondaryRes.RowsAffected()
+	if primaryErr == nil && secondaryErr == nil && primaryRows != secondaryRows {
+		d.PrimaryRowsAffected = primaryRows
+		d.SecondaryRowsAffected = secondaryRows
+		w.report(ctx, d)
+	}
+
+	return res, nil
+}
+
+func (w DualWriter) report(ctx context.Context, d Divergence) {
+	if w.OnDivergence != nil {
+		w.OnDivergence(ctx, d)
+	}
+}
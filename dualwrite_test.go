@@ -0,0 +1,75 @@
+package queries_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go-simpler.org/queries"
+	"go-simpler.org/queries/internal/assert"
+	. "go-simpler.org/queries/internal/assert/EF"
+)
+
+func TestDualWriter(t *testing.T) {
+	ctx := context.Background()
+	errDB := errors.New("database error")
+
+	tests := map[string]struct {
+		primary     *fakeDB
+		secondary   *fakeDB
+		divergences []queries.Divergence
+	}{
+		"same": {
+			primary:   &fakeDB{result: fakeResult{rowsAffected: 2}},
+			secondary: &fakeDB{result: fakeResult{rowsAffected: 2}},
+		},
+		"different rows affected": {
+			primary:   &fakeDB{result: fakeResult{rowsAffected: 2}},
+			secondary: &fakeDB{result: fakeResult{rowsAffected: 1}},
+			divergences: []queries.Divergence{{
+				Query:                 "update users set name = ?",
+				Args:                  []any{"Bob"},
+				PrimaryRowsAffected:   2,
+				SecondaryRowsAffected: 1,
+			}},
+		},
+		"secondary error": {
+			primary:   &fakeDB{result: fakeResult{rowsAffected: 2}},
+			secondary: &fakeDB{err: errDB},
+			divergences: []queries.Divergence{{
+				Query: "update users set name = ?",
+				Args:  []any{"Bob"},
+				Err:   errDB,
+			}},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var divergences []queries.Divergence
+			w := queries.DualWriter{
+				Primary:   tt.primary.open(t),
+				Secondary: tt.secondary.open(t),
+				OnDivergence: func(_ context.Context, d queries.Divergence) {
+					divergences = append(divergences, d)
+				},
+			}
+
+			res, err := queries.Exec(ctx, w, "update users set name = ?", "Bob")
+			assert.NoErr[F](t, err)
+			assert.Equal[E](t, res.RowsAffected, int64(2))
+			assert.Equal[E](t, divergences, tt.divergences)
+			assert.Equal[E](t, tt.secondary.queries, []string{"update users set name = ?"})
+		})
+	}
+
+	t.Run("primary error", func(t *testing.T) {
+		primary := fakeDB{err: errDB}
+		var secondary fakeDB
+		w := queries.DualWriter{Primary: primary.open(t), Secondary: secondary.open(t)}
+
+		_, err := queries.Exec(ctx, w, "delete from users")
+		assert.IsErr[E](t, err, errDB)
+		assert.Equal[E](t, len(secondary.queries), 0)
+	})
+}
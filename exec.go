@@ -0,0 +1,63 @@
+package queries
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+	"slices"
+)
+
+// Execer is an interface implemented by [sql.DB], [sql.Tx] and [sql.Conn].
+type Execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+// Result is the result of [Exec].
+type Result struct {
+	RowsAffected int64
+	LastInsertId int64 // zero if the driver does not support it (e.g. PostgreSQL).
+}
+
+// Exec executes the query and extracts the number of affected rows and the last inserted id from its result.
+func Exec(ctx context.Context, e Execer, query string, args ...any) (Result, error) {
+	res, err := e.ExecContext(ctx, query, args...)
+	if err != nil {
+		return Result{}, fmt.Errorf("executing query %q: %w", query, err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return Result{}, fmt.Errorf("getting rows affected: %w", err)
+	}
+
+	// not all drivers support it, so the error is ignored.
+	id, _ := res.LastInsertId()
+
+	return Result{RowsAffected: n, LastInsertId: id}, nil
+}
+
+// ExecOut executes the query (usually a stored procedure call) with the fields of out passed as output parameters.
+// The out must be a pointer to a struct; each of its fields tagged with `sql` is passed as
+// sql.Named(column, sql.Out{Dest: &field}) after the args.
+// Fields with the `inout` tag option are passed as INOUT parameters, i.e. their values are sent as input as well.
+// The driver must support output parameters (e.g. go-mssqldb, go-ora).
+func ExecOut(ctx context.Context, e Execer, query string, out any, args ...any) error {
+	v := reflect.ValueOf(out)
+	if !v.IsValid() || v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct || v.IsNil() {
+		panic("queries: out must be a non-nil struct pointer")
+	}
+
+	v = v.Elem()
+	args = slices.Clone(args)
+	for _, f := range parseStruct(v.Type()) {
+		dest := v.Field(f.index).Addr().Interface()
+		args = append(args, sql.Named(f.column, sql.Out{Dest: dest, In: f.inOut}))
+	}
+
+	if _, err := e.ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("executing query %q: %w", query, err)
+	}
+
+	return nil
+}
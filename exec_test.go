@@ -0,0 +1,48 @@
+package queries_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go-simpler.org/queries"
+	"go-simpler.org/queries/internal/assert"
+	. "go-simpler.org/queries/internal/assert/EF"
+)
+
+func TestExec(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("ok", func(t *testing.T) {
+		fdb := fakeDB{result: fakeResult{lastInsertID: 10, rowsAffected: 1}}
+		res, err := queries.Exec(ctx, fdb.open(t), "insert into users (name) values (?)", "Alice")
+		assert.NoErr[F](t, err)
+		assert.Equal[E](t, res, queries.Result{RowsAffected: 1, LastInsertId: 10})
+		assert.Equal[E](t, fdb.args, [][]any{{"Alice"}})
+	})
+
+	t.Run("error", func(t *testing.T) {
+		errDB := errors.New("database error")
+		fdb := fakeDB{err: errDB}
+		_, err := queries.Exec(ctx, fdb.open(t), "delete from users")
+		assert.IsErr[E](t, err, errDB)
+		assert.Equal[E](t, err.Error(), `executing query "delete from users": database error`)
+	})
+}
+
+func TestExecOut(t *testing.T) {
+	ctx := context.Background()
+	fdb := fakeDB{out: map[string]any{"total": 42, "status": "ok"}}
+
+	var out struct {
+		Total  int    `sql:"total"`
+		Status string `sql:"status,inout"`
+	}
+	out.Status = "new"
+
+	err := queries.ExecOut(ctx, fdb.open(t), "exec calc @p1, @total output, @status output", &out, 1)
+	assert.NoErr[F](t, err)
+	assert.Equal[E](t, out.Total, 42)
+	assert.Equal[E](t, out.Status, "ok")
+	assert.Equal[E](t, len(fdb.args[0]), 3)
+}
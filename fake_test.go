@@ -0,0 +1,140 @@
+package queries_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeDB is a minimal [driver.Connector] that returns the same rows (or result) for every query
+// and records the executed queries along with their arguments.
+type fakeDB struct {
+	columns []string
+	rows    [][]driver.Value
+	result  driver.Result
+	out     map[string]any // the values of output parameters.
+	err     error
+	delay   time.Duration // the latency of each query.
+
+	mu      sync.Mutex
+	queries []string
+	args    [][]any
+}
+
+func (f *fakeDB) open(t *testing.T) *sql.DB {
+	t.Helper()
+	db := sql.OpenDB(f)
+	t.Cleanup(func() { _ = db.Close() })
+	return db
+}
+
+func (f *fakeDB) Connect(context.Context) (driver.Conn, error) { return fakeConn{f}, nil }
+func (f *fakeDB) Driver() driver.Driver                        { return nil }
+
+type fakeConn struct{ db *fakeDB }
+
+func (c fakeConn) Prepare(query string) (driver.Stmt, error) { return fakeStmt{c, query}, nil }
+func (fakeConn) Close() error                                { return nil }
+func (fakeConn) Begin() (driver.Tx, error)                   { return fakeTx{}, nil }
+
+func (c fakeConn) QueryContext(_ context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	time.Sleep(c.db.delay)
+	c.db.record(query, args)
+	if c.db.err != nil {
+		return nil, c.db.err
+	}
+	return &fakeRows{columns: c.db.columns, rows: c.db.rows}, nil
+}
+
+// CheckNamedValue implements the [driver.NamedValueChecker] interface to support output parameters.
+func (fakeConn) CheckNamedValue(nv *driver.NamedValue) error {
+	if _, ok := nv.Value.(sql.Out); ok {
+		return nil
+	}
+	return driver.ErrSkip
+}
+
+func (c fakeConn) ExecContext(_ context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	c.db.record(query, args)
+	if c.db.err != nil {
+		return nil, c.db.err
+	}
+	for _, arg := range args {
+		if out, ok := arg.Value.(sql.Out); ok {
+			reflect.ValueOf(out.Dest).Elem().Set(reflect.ValueOf(c.db.out[arg.Name]))
+		}
+	}
+	if c.db.result == nil {
+		return driver.RowsAffected(0), nil
+	}
+	return c.db.result, nil
+}
+
+func (f *fakeDB) record(query string, args []driver.NamedValue) {
+	a := make([]any, len(args))
+	for i, arg := range args {
+		a[i] = arg.Value
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.queries = append(f.queries, query)
+	f.args = append(f.args, a)
+}
+
+// fakeStmt is a prepared statement that executes its query on the connection.
+type fakeStmt struct {
+	conn  fakeConn
+	query string
+}
+
+func (fakeStmt) Close() error  { return nil }
+func (fakeStmt) NumInput() int { return -1 }
+
+func (fakeStmt) Exec([]driver.Value) (driver.Result, error) {
+	return nil, errors.New("not implemented")
+}
+func (fakeStmt) Query([]driver.Value) (driver.Rows, error) { return nil, errors.New("not implemented") }
+
+func (s fakeStmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	return s.conn.QueryContext(ctx, s.query, args)
+}
+
+func (s fakeStmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	return s.conn.ExecContext(ctx, s.query, args)
+}
+
+type fakeTx struct{}
+
+func (fakeTx) Commit() error   { return nil }
+func (fakeTx) Rollback() error { return nil }
+
+type fakeRows struct {
+	columns []string
+	rows    [][]driver.Value
+}
+
+func (r *fakeRows) Columns() []string { return r.columns }
+func (r *fakeRows) Close() error      { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+	return nil
+}
+
+type fakeResult struct {
+	lastInsertID int64
+	rowsAffected int64
+}
+
+func (r fakeResult) LastInsertId() (int64, error) { return r.lastInsertID, nil }
+func (r fakeResult) RowsAffected() (int64, error) { return r.rowsAffected, nil }
@@ -0,0 +1,159 @@
+package queries
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"sync"
+)
+
+// ShardError is yielded by [FanOut] and [FanOutSorted] if the query fails on one of the shards.
+type ShardError struct {
+	Shard int // the index of the shard in the dbs slice.
+	Err   error
+}
+
+// Error implements the error interface.
+func (e *ShardError) Error() string { return fmt.Sprintf("shard %d: %v", e.Shard, e.Err) }
+
+// Unwrap returns the underlying error.
+func (e *ShardError) Unwrap() error { return e.Err }
+
+// FanOut executes the query on every shard concurrently and returns a single iterator over all the resulting rows,
+// in the order they arrive. If the query fails on a shard, a [*ShardError] is yielded,
+// and the iteration continues with the other shards unless the consumer stops.
+// Stopping the iteration early cancels the queries that are still running.
+func FanOut[T any](ctx context.Context, dbs []Queryer, query string, args ...any) iter.Seq2[T, error] {
+	type item struct {
+		value T
+		err   error
+	}
+
+	return func(yield func(T, error) bool) {
+		ctx, cancel := context.WithCancel(ctx)
+		items := make(chan item)
+
+		var wg sync.WaitGroup
+		for i, db := range dbs {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for t, err := range Query[T](ctx, db, query, args...) {
+					if err != nil {
+						err = &ShardError{Shard: i, Err: err}
+					}
+					select {
+					case items <- item{t, err}:
+					case <-ctx.Done():
+						return
+					}
+				}
+			}()
+		}
+		go func() {
+			wg.Wait()
+			close(items)
+		}()
+
+		defer func() {
+			cancel()
+			for range items {
+				// wait for the goroutines to stop.
+			}
+		}()
+
+		for it := range items {
+			if !yield(it.value, it.err) {
+				return
+			}
+		}
+	}
+}
+
+// FanOutSorted is like [FanOut], but merges the rows in the order defined by cmp,
+// which must match the ORDER BY clause of the query, since each shard's rows are expected to be sorted already.
+// A shard whose query fails is excluded from the merge after its [*ShardError] is yielded.
+func FanOutSorted[T any](ctx context.Context, dbs []Queryer, cmp func(a, b T) int, query string, args ...any) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		type item struct {
+			value T
+			err   error
+		}
+		type shard struct {
+			items chan item
+			value T
+		}
+
+		ctx, cancel := context.WithCancel(ctx)
+		var wg sync.WaitGroup
+		defer func() {
+			cancel()
+			wg.Wait()
+		}()
+
+		// each shard is read on its own goroutine, so all the queries run concurrently
+		// and the merge only waits for the slowest shard, not for their sum.
+		shards := make([]*shard, len(dbs))
+		for i, db := range dbs {
+			s := &shard{items: make(chan item, 1)}
+			shards[i] = s
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				defer close(s.items)
+				for t, err := range Query[T](ctx, db, query, args...) {
+					select {
+					case s.items <- item{t, err}:
+					case <-ctx.Done():
+						return
+					}
+				}
+			}()
+		}
+
+		// advance moves the shard to its next row and reports whether there is one.
+		advance := func(i int) (ok, cont bool) {
+			s := shards[i]
+			it, ok := <-s.items
+			if !ok {
+				return false, true
+			}
+			if it.err != nil {
+				return false, yield(it.value, &ShardError{Shard: i, Err: it.err})
+			}
+			s.value = it.value
+			return true, true
+		}
+
+		active := make([]int, 0, len(shards))
+		for i := range shards {
+			ok, cont := advance(i)
+			if !cont {
+				return
+			}
+			if ok {
+				active = append(active, i)
+			}
+		}
+
+		for len(active) > 0 {
+			m := 0
+			for j := 1; j < len(active); j++ {
+				if cmp(shards[active[j]].value, shards[active[m]].value) < 0 {
+					m = j
+				}
+			}
+			i := active[m]
+			if !yield(shards[i].value, nil) {
+				return
+			}
+			ok, cont := advance(i)
+			if !cont {
+				return
+			}
+			if !ok {
+				active = append(active[:m], active[m+1:]...)
+			}
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package queries_test
+
+import (
+	"cmp"
+	"context"
+	"database/sql/driver"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"go-simpler.org/queries"
+	"go-simpler.org/queries/internal/assert"
+	. "go-simpler.org/queries/internal/assert/EF"
+)
+
+func TestFanOut(t *testing.T) {
+	ctx := context.Background()
+	fdb1 := fakeDB{columns: []string{"id"}, rows: [][]driver.Value{{int64(1)}, {int64(4)}}}
+	fdb2 := fakeDB{columns: []string{"id"}, rows: [][]driver.Value{{int64(2)}, {int64(3)}, {int64(5)}}}
+	fdb3 := fakeDB{err: errors.New("foo")}
+	dbs := []queries.Queryer{fdb1.open(t), fdb2.open(t), fdb3.open(t)}
+
+	var ids []int
+	var errs []error
+	for id, err := range queries.FanOut[int](ctx, dbs, "select id from users") {
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+	assert.Equal[E](t, ids, []int{1, 2, 3, 4, 5})
+	assert.Equal[E](t, len(errs), 1)
+
+	var shardErr *queries.ShardError
+	assert.AsErr[F](t, errs[0], &shardErr)
+	assert.Equal[E](t, shardErr.Shard, 2)
+	assert.IsErr[E](t, errs[0], fdb3.err)
+}
+
+func TestFanOutSorted(t *testing.T) {
+	ctx := context.Background()
+	fdb1 := fakeDB{columns: []string{"id"}, rows: [][]driver.Value{{int64(1)}, {int64(4)}}}
+	fdb2 := fakeDB{columns: []string{"id"}, rows: [][]driver.Value{{int64(2)}, {int64(3)}, {int64(5)}}}
+	dbs := []queries.Queryer{fdb1.open(t), fdb2.open(t)}
+
+	var ids []int
+	for id, err := range queries.FanOutSorted[int](ctx, dbs, cmp.Compare[int], "select id from users order by id") {
+		assert.NoErr[F](t, err)
+		ids = append(ids, id)
+	}
+	assert.Equal[E](t, ids, []int{1, 2, 3, 4, 5})
+}
+
+func TestFanOutSorted_concurrent(t *testing.T) {
+	ctx := context.Background()
+	const delay = 100 * time.Millisecond
+
+	var dbs []queries.Queryer
+	for i := range 4 {
+		fdb := &fakeDB{columns: []string{"id"}, rows: [][]driver.Value{{int64(i)}}, delay: delay}
+		dbs = append(dbs, fdb.open(t))
+	}
+
+	start := time.Now()
+	var ids []int
+	for id, err := range queries.FanOutSorted[int](ctx, dbs, cmp.Compare[int], "select id from users order by id") {
+		assert.NoErr[F](t, err)
+		ids = append(ids, id)
+	}
+	assert.Equal[E](t, ids, []int{0, 1, 2, 3})
+	assert.Equal[E](t, time.Since(start) < 2*delay, true, "the shards are not queried concurrently")
+}
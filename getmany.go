@@ -0,0 +1,76 @@
+package queries
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+)
+
+// MissingKeysError is returned by [GetMany] if some of the keys have no rows.
+type MissingKeysError[K any] struct {
+	Keys []K
+}
+
+// Error implements the error interface.
+func (e *MissingKeysError[K]) Error() string {
+	return fmt.Sprintf("queries: no rows for %d keys: %v", len(e.Keys), e.Keys)
+}
+
+// getManyChunk is the number of keys per query, which is within the placeholder limit of every dialect.
+const getManyChunk = 1000
+
+// GetMany fetches the rows for the keys with as few queries as possible instead of a query per key.
+// The format must contain exactly one %+ verb for the keys, e.g. "select * from users where id in (%+$)" (see [QueryIn]);
+// the keys are deduplicated and split into chunks if there are many of them.
+// T must be a struct whose field with the `pk` tag option (e.g. `sql:"id,pk"`) holds the key of the row.
+// If some of the keys have no rows, the found rows are returned along with a [*MissingKeysError].
+func GetMany[T any, K comparable](ctx context.Context, q Queryer, format string, keys []K) (map[K]T, error) {
+	var pk []field
+	for _, f := range structFields[T]() {
+		if f.pk {
+			pk = append(pk, f)
+		}
+	}
+	if len(pk) != 1 {
+		panic("queries: T must have exactly one field with the `pk` tag option")
+	}
+	keyType := reflect.TypeFor[K]()
+
+	unique := uniqueKeys(keys)
+
+	rows := make(map[K]T, len(unique))
+	for t, err := range QueryIn[T](ctx, q, format, unique, getManyChunk) {
+		if err != nil {
+			return nil, err
+		}
+		key := reflect.ValueOf(t).Field(pk[0].index)
+		if !key.CanConvert(keyType) {
+			panic(fmt.Sprintf("queries: the %s key cannot be converted to %s", key.Type(), keyType))
+		}
+		rows[key.Convert(keyType).Interface().(K)] = t
+	}
+
+	var missing []K
+	for _, key := range unique {
+		if _, ok := rows[key]; !ok {
+			missing = append(missing, key)
+		}
+	}
+	if len(missing) > 0 {
+		return rows, &MissingKeysError[K]{Keys: missing}
+	}
+	return rows, nil
+}
+
+// uniqueKeys returns the keys without duplicates, keeping the order.
+func uniqueKeys[K comparable](keys []K) []K {
+	seen := make(map[K]struct{}, len(keys))
+	unique := make([]K, 0, len(keys))
+	for _, key := range keys {
+		if _, ok := seen[key]; !ok {
+			seen[key] = struct{}{}
+			unique = append(unique, key)
+		}
+	}
+	return unique
+}
@@ -0,0 +1,23 @@
+package queries_test
+
+import (
+	"context"
+	"database/sql/driver"
+	"testing"
+
+	"go-simpler.org/queries"
+	"go-simpler.org/queries/internal/assert"
+	. "go-simpler.org/queries/internal/assert/EF"
+)
+
+func TestGetMany(t *testing.T) {
+	ctx := context.Background()
+	fdb := fakeDB{columns: []string{"id", "name", "email"}, rows: [][]driver.Value{{int64(1), "Alice", ""}, {int64(3), "Carol", ""}}}
+
+	accounts, err := queries.GetMany[account](ctx, fdb.open(t), "select id, name, email from accounts where id in (%+$)", []int{1, 2, 3, 1})
+	var missing *queries.MissingKeysError[int]
+	assert.AsErr[F](t, err, &missing)
+	assert.Equal[E](t, missing.Keys, []int{2})
+	assert.Equal[E](t, accounts, map[int]account{1: {ID: 1, Name: "Alice"}, 3: {ID: 3, Name: "Carol"}})
+	assert.Equal[E](t, fdb.queries, []string{"select id, name, email from accounts where id in ($1, $2, $3)"})
+}
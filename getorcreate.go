@@ -0,0 +1,55 @@
+package queries
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// GetOrCreate inserts the row into the table unless a row with the same values of the unique columns already exists,
+// and returns the row stored in the table (e.g. with the generated id) and whether it has been created.
+// The insert ignores the conflict on the unique columns (see [Builder.AppendUpsert]), and then the row is selected by them,
+// so concurrent calls with the same unique values are safe and get the same row.
+// The zero primary key fields (see [Get]) are not inserted, so that the database generates them.
+// T must be a struct with the unique columns in its `sql` tags; it is supported for PostgreSQL, MySQL and SQLite.
+func GetOrCreate[T any](ctx context.Context, qe QueryExecer, d Dialect, table string, unique []string, row T) (T, bool, error) {
+	var zero T
+
+	v := reflect.Indirect(reflect.ValueOf(row))
+	if v.Kind() != reflect.Struct || len(unique) == 0 {
+		panic("queries: row must be a struct and unique columns must be specified")
+	}
+	fields := parseStruct(v.Type())
+
+	// the select is built first, so that the unique columns are validated before any query is executed.
+	columns := make([]string, len(fields))
+	for i, f := range fields {
+		columns[i] = f.column
+	}
+	sel := Builder{Dialect: d}
+	sel.Appendf("select %s from %s", strings.Join(columns, ", "), table)
+	for _, column := range unique {
+		i := slices.IndexFunc(fields, func(f field) bool { return f.column == column })
+		if i == -1 {
+			panic(fmt.Sprintf("queries: no field for the %#q column", column))
+		}
+		sel.Where("%s = %P", column, fields[i].value(v))
+	}
+
+	insert := Builder{Dialect: d}
+	insert.appendInsert(table, row, true, nil)
+	insert.AppendUpsert(unique, nil)
+
+	res, err := Exec(ctx, qe, insert.String(), insert.Args...)
+	if err != nil {
+		return zero, false, err
+	}
+
+	stored, err := QueryOne[T](ctx, qe, sel.String(), sel.Args...)
+	if err != nil {
+		return zero, false, err
+	}
+	return stored, res.RowsAffected > 0, nil
+}
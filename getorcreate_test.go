@@ -0,0 +1,66 @@
+package queries_test
+
+import (
+	"context"
+	"database/sql/driver"
+	"testing"
+
+	"go-simpler.org/queries"
+	"go-simpler.org/queries/internal/assert"
+	. "go-simpler.org/queries/internal/assert/EF"
+)
+
+func TestGetOrCreate(t *testing.T) {
+	ctx := context.Background()
+
+	type tag struct {
+		ID   int    `sql:"id,omitzero"`
+		Name string `sql:"name"`
+	}
+
+	for name, rowsAffected := range map[string]int64{"created": 1, "existing": 0} {
+		t.Run(name, func(t *testing.T) {
+			fdb := fakeDB{
+				columns: []string{"id", "name"},
+				rows:    [][]driver.Value{{int64(7), "go"}},
+				result:  fakeResult{rowsAffected: rowsAffected},
+			}
+
+			row, created, err := queries.GetOrCreate(ctx, fdb.open(t), queries.PostgreSQL, "tags", []string{"name"}, tag{Name: "go"})
+			assert.NoErr[F](t, err)
+			assert.Equal[E](t, row, tag{ID: 7, Name: "go"})
+			assert.Equal[E](t, created, rowsAffected == 1)
+			assert.Equal[E](t, fdb.queries, []string{
+				"insert into tags (name) values ($1) on conflict (name) do nothing",
+				"select id, name from tags where name = $1",
+			})
+			assert.Equal[E](t, fdb.args, [][]any{{"go"}, {"go"}})
+		})
+	}
+
+	t.Run("generated primary key", func(t *testing.T) {
+		type tagPK struct {
+			ID   int    `sql:"id,pk"`
+			Name string `sql:"name"`
+		}
+		fdb := fakeDB{
+			columns: []string{"id", "name"},
+			rows:    [][]driver.Value{{int64(7), "go"}},
+			result:  fakeResult{rowsAffected: 1},
+		}
+
+		row, _, err := queries.GetOrCreate(ctx, fdb.open(t), queries.PostgreSQL, "tags", []string{"name"}, tagPK{Name: "go"})
+		assert.NoErr[F](t, err)
+		assert.Equal[E](t, row, tagPK{ID: 7, Name: "go"})
+		assert.Equal[E](t, fdb.queries[0], "insert into tags (name) values ($1) on conflict (name) do nothing")
+		assert.Equal[E](t, fdb.args[0], []any{"go"})
+	})
+
+	t.Run("unknown unique column", func(t *testing.T) {
+		var fdb fakeDB
+		assert.Panics[E](t, func() {
+			_, _, _ = queries.GetOrCreate(ctx, fdb.open(t), queries.PostgreSQL, "tags", []string{"slug"}, tag{Name: "go"})
+		}, "queries: no field for the `slug` column")
+		assert.Equal[E](t, len(fdb.queries), 0)
+	})
+}
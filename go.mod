@@ -1,3 +1,3 @@
 module go-simpler.org/queries
 
-go 1.18
+go 1.23
@@ -0,0 +1,81 @@
+package queries
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// AppendInsert appends the INSERT statement for the row to the query.
+// The row must be a struct or a pointer to a struct;
+// its fields tagged with `sql` are used as the columns and the values.
+// Fields with the `omitzero` tag option are skipped if they have the zero value (e.g. to let the database generate them).
+// If the returning columns are specified, the RETURNING clause (OUTPUT for MSSQL) is added,
+// so that the query can be executed with [QueryOne] to get e.g. the generated id.
+// For Oracle, RETURNING ... INTO binds the columns to the output parameters named after them,
+// so the query must be executed with [ExecOut] instead, passing a struct with the returning columns as out.
+// MySQL does not support returning columns, use [Result.LastInsertId] instead.
+func (b *Builder) AppendInsert(table string, row any, returning ...string) {
+	b.appendInsert(table, row, false, returning)
+}
+
+// appendInsert is [Builder.AppendInsert] that optionally skips the zero `pk` fields as if they had the `omitzero` option.
+func (b *Builder) appendInsert(table string, row any, omitZeroPK bool, returning []string) {
+	v := reflect.Indirect(reflect.ValueOf(row))
+	if v.Kind() != reflect.Struct {
+		panic("queries: row must be a struct or a pointer to a struct")
+	}
+
+	var columns []string
+	var values []any
+	for _, f := range parseStruct(v.Type()) {
+		if (f.omitZero || (f.pk && omitZeroPK)) && v.Field(f.index).IsZero() {
+			continue
+		}
+		columns = append(columns, f.column)
+		values = append(values, f.value(v))
+	}
+	if len(columns) == 0 {
+		panic("queries: row has no columns to insert")
+	}
+
+	verb := b.verb()
+	fmt.Fprintf(&b.query, "insert into %s (%s)", table, strings.Join(columns, ", "))
+
+	if len(returning) > 0 {
+		if d := b.dialect(); !d.Info().Returning && d != Oracle {
+			panic(fmt.Sprintf("queries: %s does not support returning columns", d))
+		}
+		if b.dialect() == MSSQL {
+			output := make([]string, len(returning))
+			for i, column := range returning {
+				output[i] = "inserted." + column
+			}
+			fmt.Fprintf(&b.query, " output %s", strings.Join(output, ", "))
+		}
+	}
+
+	b.query.WriteString(" values (")
+	for i, value := range values {
+		if i > 0 {
+			b.query.WriteString(", ")
+		}
+		b.query.WriteString(b.bind(verb, value))
+	}
+	b.query.WriteString(")")
+
+	if len(returning) == 0 {
+		return
+	}
+	switch b.dialect() {
+	case MSSQL:
+	case Oracle:
+		into := make([]string, len(returning))
+		for i, column := range returning {
+			into[i] = ":" + column // the named output parameters of ExecOut.
+		}
+		fmt.Fprintf(&b.query, " returning %s into %s", strings.Join(returning, ", "), strings.Join(into, ", "))
+	default:
+		fmt.Fprintf(&b.query, " returning %s", strings.Join(returning, ", "))
+	}
+}
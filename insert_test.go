@@ -0,0 +1,72 @@
+package queries_test
+
+import (
+	"context"
+	"testing"
+
+	"go-simpler.org/queries"
+	"go-simpler.org/queries/internal/assert"
+	. "go-simpler.org/queries/internal/assert/EF"
+)
+
+func TestBuilder_AppendInsert(t *testing.T) {
+	type row struct {
+		ID    int            `sql:"id,omitzero"`
+		Name  string         `sql:"name"`
+		Attrs map[string]int `sql:"attrs,json"`
+	}
+
+	tests := map[string]struct {
+		dialect queries.Dialect
+		row     any
+		query   string
+		args    []any
+	}{
+		"postgres": {
+			dialect: queries.PostgreSQL,
+			row:     &row{Name: "Alice"},
+			query:   "insert into users (name, attrs) values ($1, $2) returning id",
+			args:    []any{"Alice", queries.JSON{V: map[string]int(nil)}},
+		},
+		"mssql": {
+			dialect: queries.MSSQL,
+			row:     row{ID: 1, Name: "Alice"},
+			query:   "insert into users (id, name, attrs) output inserted.id values (@p1, @p2, @p3)",
+			args:    []any{1, "Alice", queries.JSON{V: map[string]int(nil)}},
+		},
+		"oracle": {
+			dialect: queries.Oracle,
+			row:     row{Name: "Alice"},
+			query:   "insert into users (name, attrs) values (:1, :2) returning id into :id",
+			args:    []any{"Alice", queries.JSON{V: map[string]int(nil)}},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			qb := queries.Builder{Dialect: tt.dialect}
+			qb.AppendInsert("users", tt.row, "id")
+			assert.Equal[E](t, qb.String(), tt.query)
+			assert.Equal[E](t, qb.Args, tt.args)
+		})
+	}
+
+	qb := queries.Builder{Dialect: queries.MySQL}
+	assert.Panics[E](t, func() { qb.AppendInsert("users", row{}, "id") }, "queries: MySQL does not support returning columns")
+}
+
+func TestBuilder_AppendInsert_oracle(t *testing.T) {
+	ctx := context.Background()
+	fdb := fakeDB{out: map[string]any{"id": 7}}
+
+	qb := queries.Builder{Dialect: queries.Oracle}
+	qb.AppendInsert("users", user{Name: "Alice"}, "id")
+
+	var out struct {
+		ID int `sql:"id"`
+	}
+	err := queries.ExecOut(ctx, fdb.open(t), qb.String(), &out, qb.Args...)
+	assert.NoErr[F](t, err)
+	assert.Equal[E](t, out.ID, 7)
+	assert.Equal[E](t, fdb.queries, []string{"insert into users (id, name) values (:1, :2) returning id into :id"})
+}
@@ -0,0 +1,58 @@
+package queries
+
+import (
+	"context"
+	"iter"
+	"strings"
+)
+
+// InsertSeq inserts the values from seq into the table using multi-row INSERT statements of up to batchSize rows,
+// e.g. to copy the result of [Query] from another database without loading it into memory.
+// The columns are the fields of T tagged with `sql`. The batch size is reduced if needed
+// to stay within the placeholder and row limits of the dialect (see [DialectInfo]).
+// For Oracle, which has no multi-row VALUES, the rows are inserted using INSERT ALL.
+// If seq yields an error, the rows collected so far are inserted first.
+// It returns the total number of rows affected.
+func InsertSeq[T any](ctx context.Context, e Execer, d Dialect, table string, seq iter.Seq2[T, error], batchSize int) (int64, error) {
+	fields := structFields[T]()
+	if len(fields) == 0 {
+		panic("queries: T has no columns to insert")
+	}
+	if batchSize <= 0 {
+		panic("queries: batchSize must be positive")
+	}
+	info := d.Info()
+	batchSize = max(1, min(batchSize, info.MaxPlaceholders/len(fields)))
+	if info.MaxInsertRows > 0 {
+		batchSize = min(batchSize, info.MaxInsertRows)
+	}
+
+	columns := make([]string, len(fields))
+	for i, f := range fields {
+		columns[i] = f.column
+	}
+	into := "into " + table + " (" + strings.Join(columns, ", ") + ") values (%+P)"
+
+	var total int64
+	for batch, err := range Chunk(seq, batchSize) {
+		if err != nil {
+			return total, err
+		}
+		qb := Builder{Dialect: d}
+		if d == Oracle {
+			qb.Appendf("insert all")
+			for _, row := range batch {
+				qb.Appendf(" "+into, []T{row})
+			}
+			qb.Appendf(" select 1 from dual")
+		} else {
+			qb.Appendf("insert "+into, batch)
+		}
+		res, err := Exec(ctx, e, qb.String(), qb.Args...)
+		if err != nil {
+			return total, err
+		}
+		total += res.RowsAffected
+	}
+	return total, nil
+}
@@ -0,0 +1,64 @@
+package queries_test
+
+import (
+	"context"
+	"database/sql/driver"
+	"testing"
+
+	"go-simpler.org/queries"
+	"go-simpler.org/queries/internal/assert"
+	. "go-simpler.org/queries/internal/assert/EF"
+)
+
+func TestInsertSeq(t *testing.T) {
+	ctx := context.Background()
+	src := fakeDB{columns: []string{"id", "name"}, rows: [][]driver.Value{{int64(1), "Alice"}, {int64(2), "Bob"}, {int64(3), "Carol"}}}
+	dst := fakeDB{result: fakeResult{rowsAffected: 2}}
+
+	seq := queries.Query[user](ctx, src.open(t), "select id, name from users")
+	_, err := queries.InsertSeq(ctx, dst.open(t), queries.PostgreSQL, "users", seq, 2)
+	assert.NoErr[F](t, err)
+	assert.Equal[E](t, dst.queries, []string{
+		"insert into users (id, name) values ($1, $2), ($3, $4)",
+		"insert into users (id, name) values ($1, $2)",
+	})
+	assert.Equal[E](t, dst.args, [][]any{{int64(1), "Alice", int64(2), "Bob"}, {int64(3), "Carol"}})
+}
+
+func TestInsertSeq_oracle(t *testing.T) {
+	ctx := context.Background()
+	src := fakeDB{columns: []string{"id", "name"}, rows: [][]driver.Value{{int64(1), "Alice"}, {int64(2), "Bob"}, {int64(3), "Carol"}}}
+	dst := fakeDB{result: fakeResult{rowsAffected: 2}}
+
+	seq := queries.Query[user](ctx, src.open(t), "select id, name from users")
+	_, err := queries.InsertSeq(ctx, dst.open(t), queries.Oracle, "users", seq, 2)
+	assert.NoErr[F](t, err)
+	assert.Equal[E](t, dst.queries, []string{
+		"insert all into users (id, name) values (:1, :2) into users (id, name) values (:3, :4) select 1 from dual",
+		"insert all into users (id, name) values (:1, :2) select 1 from dual",
+	})
+	assert.Equal[E](t, dst.args, [][]any{{int64(1), "Alice", int64(2), "Bob"}, {int64(3), "Carol"}})
+}
+
+func TestInsertSeq_maxRows(t *testing.T) {
+	ctx := context.Background()
+	dst := fakeDB{result: fakeResult{rowsAffected: 1}}
+
+	type tag struct {
+		Name string `sql:"name"`
+	}
+	seq := func(yield func(tag, error) bool) {
+		for range 1500 {
+			if !yield(tag{Name: "go"}, nil) {
+				return
+			}
+		}
+	}
+
+	// MSSQL allows 2100 placeholders, but only 1000 rows per INSERT.
+	_, err := queries.InsertSeq(ctx, dst.open(t), queries.MSSQL, "tags", seq, 2000)
+	assert.NoErr[F](t, err)
+	assert.Equal[E](t, len(dst.args), 2)
+	assert.Equal[E](t, len(dst.args[0]), 1000)
+	assert.Equal[E](t, len(dst.args[1]), 500)
+}
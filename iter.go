@@ -0,0 +1,78 @@
+package queries
+
+import "iter"
+
+// Prefetch returns an iterator that reads up to n values ahead of the consumer from seq on a separate goroutine,
+// overlapping fetching the rows (e.g. from [Query]) with processing them.
+// If the consumer stops early, the underlying iteration is stopped as well before Prefetch returns.
+func Prefetch[T any](seq iter.Seq2[T, error], n int) iter.Seq2[T, error] {
+	if n <= 0 {
+		panic("queries: n must be positive")
+	}
+
+	type item struct {
+		value T
+		err   error
+	}
+
+	return func(yield func(T, error) bool) {
+		items := make(chan item, n)
+		done := make(chan struct{})
+
+		go func() {
+			defer close(items)
+			for value, err := range seq {
+				select {
+				case items <- item{value, err}:
+				case <-done:
+					return
+				}
+			}
+		}()
+
+		defer func() {
+			close(done)
+			for range items {
+				// wait for the goroutine to stop.
+			}
+		}()
+
+		for it := range items {
+			if !yield(it.value, it.err) {
+				return
+			}
+		}
+	}
+}
+
+// Chunk returns an iterator that groups the values from seq into batches of up to n values,
+// e.g. to process the rows from [Query] in bulk. Only the last batch may be shorter than n.
+// If seq yields an error, the values collected so far are yielded as a batch first, followed by the error.
+func Chunk[T any](seq iter.Seq2[T, error], n int) iter.Seq2[[]T, error] {
+	if n <= 0 {
+		panic("queries: n must be positive")
+	}
+
+	return func(yield func([]T, error) bool) {
+		batch := make([]T, 0, n)
+		for value, err := range seq {
+			if err != nil {
+				if len(batch) > 0 && !yield(batch, nil) {
+					return
+				}
+				yield(nil, err)
+				return
+			}
+			batch = append(batch, value)
+			if len(batch) == n {
+				if !yield(batch, nil) {
+					return
+				}
+				batch = make([]T, 0, n)
+			}
+		}
+		if len(batch) > 0 {
+			yield(batch, nil)
+		}
+	}
+}
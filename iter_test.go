@@ -0,0 +1,49 @@
+package queries_test
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"testing"
+
+	"go-simpler.org/queries"
+	"go-simpler.org/queries/internal/assert"
+	. "go-simpler.org/queries/internal/assert/EF"
+)
+
+func TestPrefetch(t *testing.T) {
+	ctx := context.Background()
+	fdb := fakeDB{columns: []string{"id"}, rows: [][]driver.Value{{int64(1)}, {int64(2)}, {int64(3)}}}
+	db := fdb.open(t)
+
+	var ids []int
+	for id, err := range queries.Prefetch(queries.Query[int](ctx, db, "select id from users"), 2) {
+		assert.NoErr[F](t, err)
+		ids = append(ids, id)
+	}
+	assert.Equal[E](t, ids, []int{1, 2, 3})
+
+	for range queries.Prefetch(queries.Query[int](ctx, db, "select id from users"), 2) {
+		break
+	}
+	assert.Equal[E](t, db.Stats().InUse, 0)
+}
+
+func TestChunk(t *testing.T) {
+	ctx := context.Background()
+	fdb := fakeDB{columns: []string{"id"}, rows: [][]driver.Value{{int64(1)}, {int64(2)}, {int64(3)}}}
+	db := fdb.open(t)
+
+	var batches [][]int
+	for batch, err := range queries.Chunk(queries.Query[int](ctx, db, "select id from users"), 2) {
+		assert.NoErr[F](t, err)
+		batches = append(batches, batch)
+	}
+	assert.Equal[E](t, batches, [][]int{{1, 2}, {3}})
+
+	fdb.err = errors.New("foo")
+	for batch, err := range queries.Chunk(queries.Query[int](ctx, db, "select id from users"), 2) {
+		assert.IsErr[E](t, err, fdb.err)
+		assert.Equal[E](t, len(batch), 0)
+	}
+}
@@ -0,0 +1,70 @@
+package queries
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"io"
+	"iter"
+)
+
+// JSON wraps a value stored in a JSON column.
+// It implements [sql.Scanner] (V must be a pointer then) and [driver.Valuer].
+// Struct fields with the `json` tag option (e.g. `sql:"payload,json"`) are wrapped automatically when scanning.
+type JSON struct{ V any }
+
+// Scan implements the [sql.Scanner] interface.
+// NULL leaves V unchanged.
+func (j JSON) Scan(src any) error {
+	switch src := src.(type) {
+	case nil:
+		return nil
+	case []byte:
+		return json.Unmarshal(src, j.V)
+	case string:
+		return json.Unmarshal([]byte(src), j.V)
+	default:
+		return fmt.Errorf("queries: cannot unmarshal %T as JSON", src)
+	}
+}
+
+// Value implements the [driver.Valuer] interface.
+func (j JSON) Value() (driver.Value, error) {
+	return json.Marshal(j.V)
+}
+
+// EncodeJSON writes the values yielded by seq to w as a JSON array, one element at a time,
+// so that the result of [Query] can be streamed e.g. to an HTTP response without buffering all the rows.
+// If seq yields an error, encoding stops and the error is returned.
+// Nothing is written if the error is yielded before the first value, so the caller can still report it properly;
+// otherwise the array is left unterminated, so the output is never mistaken for a complete result.
+func EncodeJSON[T any](w io.Writer, seq iter.Seq2[T, error]) error {
+	var n int
+	for v, err := range seq {
+		if err != nil {
+			return err
+		}
+		data, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Errorf("marshaling row %d: %w", n, err)
+		}
+		sep := ","
+		if n == 0 {
+			sep = "["
+		}
+		if _, err := io.WriteString(w, sep); err != nil {
+			return err
+		}
+		if _, err := w.Write(data); err != nil {
+			return err
+		}
+		n++
+	}
+
+	end := "]"
+	if n == 0 {
+		end = "[]"
+	}
+	_, err := io.WriteString(w, end)
+	return err
+}
@@ -0,0 +1,52 @@
+package queries_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"go-simpler.org/queries"
+	"go-simpler.org/queries/internal/assert"
+	. "go-simpler.org/queries/internal/assert/EF"
+)
+
+func TestEncodeJSON(t *testing.T) {
+	type row struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	}
+	errFoo := errors.New("foo")
+
+	seq := func(n int, err error) func(func(row, error) bool) {
+		return func(yield func(row, error) bool) {
+			for i := range n {
+				if !yield(row{ID: i + 1, Name: "a"}, nil) {
+					return
+				}
+			}
+			if err != nil {
+				yield(row{}, err)
+			}
+		}
+	}
+
+	tests := map[string]struct {
+		n      int
+		err    error
+		output string
+	}{
+		"empty":            {0, nil, `[]`},
+		"rows":             {2, nil, `[{"id":1,"name":"a"},{"id":2,"name":"a"}]`},
+		"error first":      {0, errFoo, ``},
+		"error mid-stream": {1, errFoo, `[{"id":1,"name":"a"}`},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var sb strings.Builder
+			err := queries.EncodeJSON(&sb, seq(tt.n, tt.err))
+			assert.IsErr[E](t, err, tt.err)
+			assert.Equal[E](t, sb.String(), tt.output)
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package queries
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// AppendKeyset appends the keyset (cursor) pagination clause to the query, e.g.
+// " and (a, b) > ($1, $2) order by a, b limit $3".
+// The cursor must be a pointer to a struct whose fields tagged with `sql` are the sort key columns in order.
+// A nil pointer means the first page, so only the ORDER BY and LIMIT parts are appended.
+// Since the condition starts with AND, the query must already have a WHERE clause (e.g. "where 1=1").
+// Row values are used for PostgreSQL and SQLite; for other dialects the condition is expanded to
+// "(a > ?) or (a = ? and b > ?)", since they either do not support row values or do not use indexes for them.
+// Use [KeysetCursor] to get the cursor for the next page.
+func (b *Builder) AppendKeyset(cursor any, desc bool, limit int) {
+	v := reflect.ValueOf(cursor)
+	if !v.IsValid() || v.Kind() != reflect.Ptr || v.Type().Elem().Kind() != reflect.Struct {
+		panic("queries: cursor must be a struct pointer")
+	}
+
+	fields := parseStruct(v.Type().Elem())
+	if len(fields) == 0 {
+		panic("queries: cursor has no columns")
+	}
+
+	columns := make([]string, len(fields))
+	for i, f := range fields {
+		columns[i] = f.column
+	}
+
+	op, order := ">", ""
+	if desc {
+		op, order = "<", " desc"
+	}
+
+	verb := b.verb()
+	if !v.IsNil() {
+		v = v.Elem()
+		if verb == '$' || b.Dialect == SQLite {
+			values := make([]string, len(fields))
+			for i, f := range fields {
+				values[i] = b.bind(verb, f.value(v))
+			}
+			fmt.Fprintf(&b.query, " and (%s) %s (%s)", strings.Join(columns, ", "), op, strings.Join(values, ", "))
+		} else {
+			var or []string
+			for i := range fields {
+				var and []string
+				for j := 0; j < i; j++ {
+					and = append(and, fmt.Sprintf("%s = %s", columns[j], b.bind(verb, fields[j].value(v))))
+				}
+				and = append(and, fmt.Sprintf("%s %s %s", columns[i], op, b.bind(verb, fields[i].value(v))))
+				or = append(or, "("+strings.Join(and, " and ")+")")
+			}
+			fmt.Fprintf(&b.query, " and (%s)", strings.Join(or, " or "))
+		}
+	}
+
+	b.query.WriteString(" order by ")
+	for i, column := range columns {
+		if i > 0 {
+			b.query.WriteString(", ")
+		}
+		b.query.WriteString(column + order)
+	}
+
+	if b.offsetFetch() {
+		fmt.Fprintf(&b.query, " offset 0 rows fetch next %s rows only", b.bind(verb, limit))
+		return
+	}
+	fmt.Fprintf(&b.query, " limit %s", b.bind(verb, limit))
+}
+
+// KeysetCursor returns the cursor of type C for the next page after the row (see [Builder.AppendKeyset]).
+// The row must be a struct or a pointer to a struct with a field for each column of C.
+func KeysetCursor[C any](row any) *C {
+	v := reflect.Indirect(reflect.ValueOf(row))
+	if v.Kind() != reflect.Struct {
+		panic("queries: row must be a struct or a pointer to a struct")
+	}
+
+	rowFields := make(map[string]field)
+	for _, f := range parseStruct(v.Type()) {
+		rowFields[f.column] = f
+	}
+
+	cursor := new(C)
+	c := reflect.ValueOf(cursor).Elem()
+	for _, f := range parseStruct(c.Type()) {
+		rf, ok := rowFields[f.column]
+		if !ok {
+			panic(fmt.Sprintf("queries: row has no field for the %#q column", f.column))
+		}
+		c.Field(f.index).Set(v.Field(rf.index))
+	}
+
+	return cursor
+}
@@ -0,0 +1,69 @@
+package queries_test
+
+import (
+	"testing"
+
+	"go-simpler.org/queries"
+	"go-simpler.org/queries/internal/assert"
+	. "go-simpler.org/queries/internal/assert/EF"
+)
+
+func TestBuilder_AppendKeyset(t *testing.T) {
+	type cursor struct {
+		CreatedAt int `sql:"created_at"`
+		ID        int `sql:"id"`
+	}
+
+	tests := map[string]struct {
+		dialect queries.Dialect
+		cursor  *cursor
+		desc    bool
+		query   string
+		args    []any
+	}{
+		"first page": {
+			dialect: queries.PostgreSQL,
+			cursor:  nil,
+			query:   "select * from users where 1=1 order by created_at, id limit $1",
+			args:    []any{10},
+		},
+		"row values": {
+			dialect: queries.PostgreSQL,
+			cursor:  &cursor{CreatedAt: 100, ID: 5},
+			query:   "select * from users where 1=1 and (created_at, id) > ($1, $2) order by created_at, id limit $3",
+			args:    []any{100, 5, 10},
+		},
+		"expanded desc": {
+			dialect: queries.MySQL,
+			cursor:  &cursor{CreatedAt: 100, ID: 5},
+			desc:    true,
+			query:   "select * from users where 1=1 and ((created_at < ?) or (created_at = ? and id < ?)) order by created_at desc, id desc limit ?",
+			args:    []any{100, 100, 5, 10},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			qb := queries.Builder{Dialect: tt.dialect}
+			qb.Appendf("select * from users where 1=1")
+			qb.AppendKeyset(tt.cursor, tt.desc, 10)
+			assert.Equal[E](t, qb.String(), tt.query)
+			assert.Equal[E](t, qb.Args, tt.args)
+		})
+	}
+}
+
+func TestKeysetCursor(t *testing.T) {
+	type row struct {
+		ID        int    `sql:"id"`
+		Name      string `sql:"name"`
+		CreatedAt int    `sql:"created_at"`
+	}
+	type cursor struct {
+		CreatedAt int `sql:"created_at"`
+		ID        int `sql:"id"`
+	}
+
+	c := queries.KeysetCursor[cursor](row{ID: 5, Name: "Alice", CreatedAt: 100})
+	assert.Equal[E](t, c, &cursor{CreatedAt: 100, ID: 5})
+}
@@ -0,0 +1,23 @@
+package queries
+
+import "fmt"
+
+// AppendLimitOffset appends the pagination clause for the dialect to the query:
+// " limit $1 offset $2" for PostgreSQL, MySQL and SQLite,
+// and " offset @p1 rows fetch next @p2 rows only" for MSSQL and Oracle (MSSQL requires the query to have ORDER BY).
+func (b *Builder) AppendLimitOffset(limit, offset int) {
+	verb := b.verb()
+	if b.offsetFetch() {
+		fmt.Fprintf(&b.query, " offset %s rows fetch next %s rows only", b.bind(verb, offset), b.bind(verb, limit))
+		return
+	}
+	fmt.Fprintf(&b.query, " limit %s offset %s", b.bind(verb, limit), b.bind(verb, offset))
+}
+
+// offsetFetch reports whether the dialect paginates with OFFSET ... FETCH NEXT (see [DialectInfo.OffsetFetch]).
+func (b *Builder) offsetFetch() bool {
+	if b.Dialect == 0 && b.placeholder == '?' {
+		return false // both MySQL and SQLite use LIMIT, so there is no need to tell them apart.
+	}
+	return b.dialect().Info().OffsetFetch
+}
@@ -0,0 +1,36 @@
+package queries
+
+import "fmt"
+
+// LockMode is a row locking mode for SELECT statements.
+// See [Builder.AppendLock].
+type LockMode int
+
+const (
+	ForUpdate LockMode = iota + 1
+	ForUpdateSkipLocked
+	ForShare
+)
+
+// AppendLock appends the row locking clause for the dialect (e.g. " for update") to the query.
+// It panics if the dialect does not support locking clauses (SQLite, MSSQL) or the mode (Oracle does not support [ForShare]).
+func (b *Builder) AppendLock(mode LockMode) {
+	d := b.dialect()
+	switch {
+	case d == PostgreSQL, d == MySQL:
+	case d == Oracle && mode != ForShare:
+	default:
+		panic(fmt.Sprintf("queries: %s does not support row locking clauses", d))
+	}
+
+	switch mode {
+	case ForUpdate:
+		b.query.WriteString(" for update")
+	case ForUpdateSkipLocked:
+		b.query.WriteString(" for update skip locked")
+	case ForShare:
+		b.query.WriteString(" for share")
+	default:
+		panic("queries: unknown lock mode")
+	}
+}
@@ -0,0 +1,175 @@
+package queries
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// BindNamed replaces the :name parameters of the query (sqlx-style) with the placeholders of the dialect
+// and returns the query along with the arguments taken from arg,
+// which must be either a struct (see [BindStruct]) or a map[string]any.
+// A parameter used several times is bound once for the dialects with numbered placeholders.
+// The :: cast operator, string literals, quoted identifiers and comments are left as is.
+func BindNamed(d Dialect, query string, arg any) (string, []any, error) {
+	if m, ok := arg.(map[string]any); ok {
+		return BindMap(d, query, m)
+	}
+	return BindStruct(d, query, arg)
+}
+
+// BindMap is like [BindNamed], but the arguments are taken from the map.
+// The arguments are in the order of the parameters in the query, so the result is the same across runs.
+// All the parameters missing from the map are reported in the error.
+func BindMap(d Dialect, query string, args map[string]any) (string, []any, error) {
+	return bindNamed(d, query, func(name string) (any, bool) {
+		v, ok := args[name]
+		return v, ok
+	})
+}
+
+// BindStruct is like [BindNamed], but the arguments are taken from the fields of the struct arg (or a pointer to it)
+// tagged with `sql`, e.g. :name is replaced with the value of the `sql:"name"` field.
+// The tag options (e.g. `json`) are applied to the values.
+func BindStruct(d Dialect, query string, arg any) (string, []any, error) {
+	v := reflect.Indirect(reflect.ValueOf(arg))
+	if v.Kind() != reflect.Struct {
+		panic("queries: arg must be a struct or a pointer to a struct")
+	}
+	fields := make(map[string]field)
+	for _, f := range parseStruct(v.Type()) {
+		fields[f.column] = f
+	}
+	return bindNamed(d, query, func(name string) (any, bool) {
+		f, ok := fields[name]
+		if !ok {
+			return nil, false
+		}
+		return f.value(v), true
+	})
+}
+
+// bindNamed replaces the :name parameters of the query with the placeholders of the dialect,
+// using lookup to get their values.
+func bindNamed(d Dialect, query string, lookup func(name string) (any, bool)) (string, []any, error) {
+	b := Builder{Dialect: d}
+	verb := dialectVerb(d)
+	bound := make(map[string]string)
+
+	var missing []string
+	query = walkSQL(query, func(code string) string {
+		var sb strings.Builder
+		for i := 0; i < len(code); i++ {
+			if code[i] != ':' {
+				sb.WriteByte(code[i])
+				continue
+			}
+			if i+1 < len(code) && code[i+1] == ':' { // a cast, e.g. ::text.
+				sb.WriteString("::")
+				i++
+				continue
+			}
+			j := i + 1
+			for j < len(code) && isIdentByte(code[j], j == i+1) {
+				j++
+			}
+			if j == i+1 { // not a parameter.
+				sb.WriteByte(':')
+				continue
+			}
+
+			name := code[i+1 : j]
+			i = j - 1
+
+			p, ok := bound[name]
+			if !ok || verb == '?' {
+				value, found := lookup(name)
+				if !found {
+					if !slices.Contains(missing, ":"+name) {
+						missing = append(missing, ":"+name)
+					}
+					continue
+				}
+				p = b.bind(verb, value)
+				bound[name] = p
+			}
+			sb.WriteString(p)
+		}
+		return sb.String()
+	})
+	switch len(missing) {
+	case 0:
+		return query, b.Args, nil
+	case 1:
+		return "", nil, fmt.Errorf("queries: no value for the %s parameter", missing[0])
+	default:
+		return "", nil, fmt.Errorf("queries: no values for the %s parameters", strings.Join(missing, ", "))
+	}
+}
+
+func isIdentByte(c byte, first bool) bool {
+	switch {
+	case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c == '_':
+		return true
+	case c >= '0' && c <= '9':
+		return !first
+	default:
+		return false
+	}
+}
+
+// walkSQL calls fn for each part of the query outside string literals, quoted identifiers and comments,
+// and returns the query with these parts replaced with the results.
+func walkSQL(query string, fn func(code string) string) string {
+	var sb strings.Builder
+	start := 0 // the start of the current code part.
+	for i := 0; i < len(query); {
+		var open, end string
+		switch {
+		case query[i] == '\'', query[i] == '"', query[i] == '`':
+			open, end = query[i:i+1], query[i:i+1] // an escaped quote ('') is handled as two adjacent literals.
+		case strings.HasPrefix(query[i:], "--"):
+			open, end = "--", "\n"
+		case strings.HasPrefix(query[i:], "/*"):
+			open, end = "/*", "*/"
+		default:
+			i++
+			continue
+		}
+
+		j := len(query) // an unterminated literal or comment lasts until the end.
+		if k := strings.Index(query[i+len(open):], end); k != -1 {
+			j = i + len(open) + k + len(end)
+		}
+		sb.WriteString(fn(query[start:i]))
+		sb.WriteString(query[i:j])
+		i, start = j, j
+	}
+	sb.WriteString(fn(query[start:]))
+	return sb.String()
+}
+
+// Rebind rewrites the placeholders of the query from one dialect to another, e.g. to run MySQL queries with ? on PostgreSQL.
+// String literals, quoted identifiers and comments are left as is.
+// Numbered placeholders can be converted to ? only if they are used once and in order.
+func Rebind(query string, from, to Dialect) string {
+	fromVerb, toVerb := dialectVerb(from), dialectVerb(to)
+	if fromVerb == toVerb {
+		return query
+	}
+
+	var count int
+	return walkSQL(query, func(code string) string {
+		return replacePlaceholders(code, fromVerb, func(n int) string {
+			count++
+			if fromVerb == '?' {
+				n = count
+			}
+			if toVerb == '?' && n != count {
+				panic(fmt.Sprintf("queries: cannot rebind %s to ?, placeholders must be used once and in order", placeholder(fromVerb, n)))
+			}
+			return placeholder(toVerb, n)
+		})
+	})
+}
@@ -0,0 +1,89 @@
+package queries_test
+
+import (
+	"testing"
+
+	"go-simpler.org/queries"
+	"go-simpler.org/queries/internal/assert"
+	. "go-simpler.org/queries/internal/assert/EF"
+)
+
+func TestBindNamed(t *testing.T) {
+	const query = `select id::text, ':skipped' from users -- :comment
+where name = :name /* :comment */ and (id = :id or parent_id = :id)`
+
+	tests := map[queries.Dialect]struct {
+		query string
+		args  []any
+	}{
+		queries.PostgreSQL: {
+			query: `select id::text, ':skipped' from users -- :comment
+where name = $1 /* :comment */ and (id = $2 or parent_id = $2)`,
+			args: []any{"Alice", 1},
+		},
+		queries.MySQL: {
+			query: `select id::text, ':skipped' from users -- :comment
+where name = ? /* :comment */ and (id = ? or parent_id = ?)`,
+			args: []any{"Alice", 1, 1},
+		},
+	}
+
+	for dialect, tt := range tests {
+		t.Run(dialect.String(), func(t *testing.T) {
+			q, args, err := queries.BindNamed(dialect, query, user{ID: 1, Name: "Alice"})
+			assert.NoErr[F](t, err)
+			assert.Equal[E](t, q, tt.query)
+			assert.Equal[E](t, args, tt.args)
+
+			q, args, err = queries.BindNamed(dialect, query, map[string]any{"id": 1, "name": "Alice"})
+			assert.NoErr[F](t, err)
+			assert.Equal[E](t, q, tt.query)
+			assert.Equal[E](t, args, tt.args)
+		})
+	}
+
+	t.Run("missing", func(t *testing.T) {
+		_, _, err := queries.BindNamed(queries.PostgreSQL, "select * from users where age = :age", &user{})
+		assert.Equal[E](t, err.Error(), "queries: no value for the :age parameter")
+	})
+}
+
+func TestBindStruct(t *testing.T) {
+	type event struct {
+		ID      int            `sql:"id"`
+		Payload map[string]int `sql:"payload,json"`
+	}
+
+	query, args, err := queries.BindStruct(queries.Oracle, "insert into events (id, payload) values (:id, :payload)", &event{ID: 1, Payload: map[string]int{"a": 1}})
+	assert.NoErr[F](t, err)
+	assert.Equal[E](t, query, "insert into events (id, payload) values (:1, :2)")
+	assert.Equal[E](t, args, []any{1, queries.JSON{V: map[string]int{"a": 1}}})
+
+	assert.Panics[E](t, func() { _, _, _ = queries.BindStruct(queries.Oracle, "select :id", 1) }, "queries: arg must be a struct or a pointer to a struct")
+}
+
+func TestBindMap(t *testing.T) {
+	const query = "select * from users where name = :name and age > :age and city = :city and id != :age"
+
+	q, args, err := queries.BindMap(queries.PostgreSQL, query, map[string]any{"name": "Alice", "age": 18, "city": "Paris"})
+	assert.NoErr[F](t, err)
+	assert.Equal[E](t, q, "select * from users where name = $1 and age > $2 and city = $3 and id != $2")
+	assert.Equal[E](t, args, []any{"Alice", 18, "Paris"})
+
+	_, _, err = queries.BindMap(queries.PostgreSQL, query, map[string]any{"name": "Alice"})
+	assert.Equal[E](t, err.Error(), "queries: no values for the :age, :city parameters")
+}
+
+func TestRebind(t *testing.T) {
+	const query = "select * from users where name = ? and note != '?' -- ?\nand id in (?, ?)"
+
+	pg := queries.Rebind(query, queries.MySQL, queries.PostgreSQL)
+	assert.Equal[E](t, pg, "select * from users where name = $1 and note != '?' -- ?\nand id in ($2, $3)")
+
+	mssql := queries.Rebind(pg, queries.PostgreSQL, queries.MSSQL)
+	assert.Equal[E](t, mssql, "select * from users where name = @p1 and note != '?' -- ?\nand id in (@p2, @p3)")
+
+	assert.Equal[E](t, queries.Rebind(mssql, queries.MSSQL, queries.SQLite), query)
+	assert.Panics[E](t, func() { queries.Rebind("select $2, $1", queries.PostgreSQL, queries.MySQL) },
+		"queries: cannot rebind $2 to ?, placeholders must be used once and in order")
+}
@@ -0,0 +1,61 @@
+package queries
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrBadOrderBy is returned by [Builder.AppendOrderBy] if the input contains an unknown sort key or direction.
+var ErrBadOrderBy = errors.New("queries: bad order by")
+
+// AppendOrderBy appends the ORDER BY clause built from user input (e.g. a query string parameter) to the query.
+// The input is a comma-separated list of sort keys, each optionally prefixed with "-" or followed by "asc" or "desc",
+// e.g. "name,-created_at" or "name asc, created_at desc".
+// The keys are mapped to the columns (or expressions) of the allowed map; anything else is rejected with [ErrBadOrderBy].
+// Nothing is appended if the input is empty.
+func (b *Builder) AppendOrderBy(input string, allowed map[string]string) error {
+	input = strings.TrimSpace(input)
+	if input == "" {
+		return nil
+	}
+
+	var terms []string
+	for _, item := range strings.Split(input, ",") {
+		key, dir, err := parseSortKey(item)
+		if err != nil {
+			return err
+		}
+		column, ok := allowed[key]
+		if !ok {
+			return fmt.Errorf("%w: unknown sort key %q", ErrBadOrderBy, key)
+		}
+		terms = append(terms, column+" "+dir)
+	}
+
+	b.query.WriteString(" order by " + strings.Join(terms, ", "))
+	return nil
+}
+
+// parseSortKey returns the key and the direction ("asc" or "desc") of a single sort item.
+func parseSortKey(item string) (key, dir string, _ error) {
+	fields := strings.Fields(item)
+	switch len(fields) {
+	case 1:
+		key, dir = fields[0], "asc"
+		if rest, ok := strings.CutPrefix(key, "-"); ok {
+			key, dir = rest, "desc"
+		}
+	case 2:
+		key, dir = fields[0], strings.ToLower(fields[1])
+		if dir != "asc" && dir != "desc" {
+			return "", "", fmt.Errorf("%w: unknown sort direction %q", ErrBadOrderBy, fields[1])
+		}
+	default:
+		return "", "", fmt.Errorf("%w: bad sort item %q", ErrBadOrderBy, item)
+	}
+	if key == "" {
+		return "", "", fmt.Errorf("%w: empty sort key", ErrBadOrderBy)
+	}
+	return key, dir, nil
+}
@@ -0,0 +1,45 @@
+package queries_test
+
+import (
+	"testing"
+
+	"go-simpler.org/queries"
+	"go-simpler.org/queries/internal/assert"
+	. "go-simpler.org/queries/internal/assert/EF"
+)
+
+func TestBuilder_AppendOrderBy(t *testing.T) {
+	allowed := map[string]string{
+		"name":    "u.name",
+		"created": "u.created_at",
+	}
+
+	tests := map[string]struct {
+		input string
+		query string
+		err   string
+	}{
+		"empty":             {input: "", query: "select * from users u"},
+		"prefix":            {input: "name,-created", query: "select * from users u order by u.name asc, u.created_at desc"},
+		"direction":         {input: "name DESC, created asc", query: "select * from users u order by u.name desc, u.created_at asc"},
+		"unknown key":       {input: "password", err: `queries: bad order by: unknown sort key "password"`},
+		"unknown direction": {input: "name up", err: `queries: bad order by: unknown sort direction "up"`},
+		"injection":         {input: "name; drop table users", err: `queries: bad order by: bad sort item "name; drop table users"`},
+		"empty key":         {input: "name,", err: "queries: bad order by: bad sort item \"\""},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var qb queries.Builder
+			qb.Appendf("select * from users u")
+			err := qb.AppendOrderBy(tt.input, allowed)
+			if tt.err != "" {
+				assert.IsErr[E](t, err, queries.ErrBadOrderBy)
+				assert.Equal[E](t, err.Error(), tt.err)
+				return
+			}
+			assert.NoErr[F](t, err)
+			assert.Equal[E](t, qb.String(), tt.query)
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package queries
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// QueryPage executes the query built by qb with the page clause appended (e.g. " order by id limit %$ offset %$")
+// and the COUNT(*) variant of the query without the page clause concurrently.
+// It returns the page of rows scanned into T and the total number of rows.
+// The query itself should not contain ORDER BY, since some databases (e.g. MSSQL) do not allow it in subqueries;
+// put it in the page clause instead.
+// Since the queries are executed concurrently, q should be a [sql.DB] rather than a [sql.Tx] or a [sql.Conn].
+func QueryPage[T any](ctx context.Context, q Queryer, qb *Builder, pageFormat string, pageArgs ...any) ([]T, int64, error) {
+	query := qb.String()
+
+	page := qb.clone()
+	page.Appendf(pageFormat, pageArgs...)
+	pageQuery := page.String()
+
+	var (
+		wg       sync.WaitGroup
+		total    int64
+		countErr error
+	)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		total, countErr = QueryOne[int64](ctx, q, fmt.Sprintf("select count(*) from (%s) t", query), qb.Args...)
+	}()
+
+	var items []T
+	var err error
+	for t, e := range Query[T](ctx, q, pageQuery, page.Args...) {
+		if e != nil {
+			err = e
+			break
+		}
+		items = append(items, t)
+	}
+
+	wg.Wait()
+	if err != nil {
+		return nil, 0, err
+	}
+	if countErr != nil {
+		return nil, 0, countErr
+	}
+
+	return items, total, nil
+}
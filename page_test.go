@@ -0,0 +1,36 @@
+package queries_test
+
+import (
+	"context"
+	"database/sql/driver"
+	"testing"
+
+	"go-simpler.org/queries"
+	"go-simpler.org/queries/internal/assert"
+	. "go-simpler.org/queries/internal/assert/EF"
+)
+
+func TestQueryPage(t *testing.T) {
+	ctx := context.Background()
+	fdb := fakeDB{columns: []string{"count"}, rows: [][]driver.Value{{int64(42)}}}
+
+	var qb queries.Builder
+	qb.Appendf("select count from tbl where foo = %$", 1)
+
+	items, total, err := queries.QueryPage[int](ctx, fdb.open(t), &qb, " order by count limit %$ offset %$", 10, 20)
+	assert.NoErr[F](t, err)
+	assert.Equal[E](t, items, []int{42})
+	assert.Equal[E](t, total, int64(42))
+	assert.Equal[E](t, qb.String(), "select count from tbl where foo = $1")
+	assert.Equal[E](t, qb.Args, []any{1})
+
+	// the queries run concurrently, so their order is not deterministic.
+	executed := make(map[string][]any)
+	for i, query := range fdb.queries {
+		executed[query] = fdb.args[i]
+	}
+	assert.Equal[E](t, executed, map[string][]any{
+		"select count(*) from (select count from tbl where foo = $1) t":          {int64(1)},
+		"select count from tbl where foo = $1 order by count limit $2 offset $3": {int64(1), int64(10), int64(20)},
+	})
+}
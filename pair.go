@@ -0,0 +1,118 @@
+package queries
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"reflect"
+	"strings"
+)
+
+// Pair is a row of a JOIN query scanned into two structs.
+type Pair[A, B any] struct {
+	First  A
+	Second B
+}
+
+// QueryPair executes the query and scans each row into a pair of structs, e.g. for a JOIN of two tables.
+// The columns are split by the prefixes, which are stripped before matching the columns with the `sql` tags:
+//
+//	select o.id as "o.id", o.total as "o.total", c.id as "c.id", c.name as "c.name"
+//	from orders o join customers c on c.id = o.customer_id
+//
+// Each column must start with one of the prefixes.
+func QueryPair[A, B any](ctx context.Context, q Queryer, prefixA, prefixB, query string, args ...any) iter.Seq2[Pair[A, B], error] {
+	typA, typB := reflect.TypeFor[A](), reflect.TypeFor[B]()
+	if !isStruct(typA) || !isStruct(typB) {
+		panic("queries: A and B must be structs")
+	}
+	if prefixA == "" || prefixB == "" || strings.HasPrefix(prefixA, prefixB) || strings.HasPrefix(prefixB, prefixA) {
+		panic("queries: prefixes must be non-empty and distinct")
+	}
+
+	return func(yield func(Pair[A, B], error) bool) {
+		var zero Pair[A, B]
+
+		rows, err := q.QueryContext(ctx, query, args...)
+		if err != nil {
+			yield(zero, fmt.Errorf("executing query %q: %w", query, err))
+			return
+		}
+		defer rows.Close()
+
+		columns, err := rows.Columns()
+		if err != nil {
+			yield(zero, fmt.Errorf("getting column names: %w", err))
+			return
+		}
+
+		o := scanOptions{fold: isFoldCase(q)}
+		cutPrefix := func(column, prefix string) (string, bool) {
+			if name, ok := strings.CutPrefix(column, prefix); ok {
+				return name, true
+			}
+			if o.fold && len(column) >= len(prefix) && strings.EqualFold(column[:len(prefix)], prefix) {
+				return column[len(prefix):], true
+			}
+			return "", false
+		}
+
+		// first[i] reports whether the i-th column belongs to A.
+		first := make([]bool, len(columns))
+		var columnsA, columnsB []string
+		for i, column := range columns {
+			if name, ok := cutPrefix(column, prefixA); ok {
+				first[i] = true
+				columnsA = append(columnsA, name)
+			} else if name, ok := cutPrefix(column, prefixB); ok {
+				columnsB = append(columnsB, name)
+			} else {
+				yield(zero, fmt.Errorf("queries: the %#q column has neither the %q nor the %q prefix", column, prefixA, prefixB))
+				return
+			}
+		}
+
+		fieldsA, err := columnFields(typA, columnsA, o)
+		if err != nil {
+			yield(zero, err)
+			return
+		}
+		fieldsB, err := columnFields(typB, columnsB, o)
+		if err != nil {
+			yield(zero, err)
+			return
+		}
+
+		for rows.Next() {
+			if err := ctx.Err(); err != nil {
+				yield(zero, err)
+				return
+			}
+
+			var p Pair[A, B]
+			va, vb := reflect.ValueOf(&p.First).Elem(), reflect.ValueOf(&p.Second).Elem()
+			target := make([]any, len(columns))
+			var a, b int
+			for i := range columns {
+				if first[i] {
+					target[i] = fieldsA[a].target(va)
+					a++
+				} else {
+					target[i] = fieldsB[b].target(vb)
+					b++
+				}
+			}
+
+			if err := rows.Scan(target...); err != nil {
+				yield(zero, fmt.Errorf("scanning rows: %w", err))
+				return
+			}
+			if !yield(p, nil) {
+				return
+			}
+		}
+		if err := rows.Err(); err != nil {
+			yield(zero, err)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package queries_test
+
+import (
+	"context"
+	"database/sql/driver"
+	"testing"
+
+	"go-simpler.org/queries"
+	"go-simpler.org/queries/internal/assert"
+	. "go-simpler.org/queries/internal/assert/EF"
+)
+
+func TestQueryPair(t *testing.T) {
+	ctx := context.Background()
+
+	type order struct {
+		ID    int `sql:"id"`
+		Total int `sql:"total"`
+	}
+
+	t.Run("ok", func(t *testing.T) {
+		fdb := fakeDB{
+			columns: []string{"o.id", "c.name", "o.total", "c.id"},
+			rows:    [][]driver.Value{{int64(10), "Alice", int64(100), int64(1)}, {int64(11), "Bob", int64(200), int64(2)}},
+		}
+
+		var pairs []queries.Pair[order, user]
+		for p, err := range queries.QueryPair[order, user](ctx, fdb.open(t), "o.", "c.", "select ...") {
+			assert.NoErr[F](t, err)
+			pairs = append(pairs, p)
+		}
+		assert.Equal[E](t, pairs, []queries.Pair[order, user]{
+			{First: order{ID: 10, Total: 100}, Second: user{ID: 1, Name: "Alice"}},
+			{First: order{ID: 11, Total: 200}, Second: user{ID: 2, Name: "Bob"}},
+		})
+	})
+
+	t.Run("fold case", func(t *testing.T) {
+		fdb := fakeDB{
+			columns: []string{"O.ID", "C.NAME", "O.TOTAL", "C.ID"},
+			rows:    [][]driver.Value{{int64(10), "Alice", int64(100), int64(1)}},
+		}
+
+		var pairs []queries.Pair[order, user]
+		for p, err := range queries.QueryPair[order, user](ctx, queries.FoldCase{QueryExecer: fdb.open(t)}, "o.", "c.", "select ...") {
+			assert.NoErr[F](t, err)
+			pairs = append(pairs, p)
+		}
+		assert.Equal[E](t, pairs, []queries.Pair[order, user]{
+			{First: order{ID: 10, Total: 100}, Second: user{ID: 1, Name: "Alice"}},
+		})
+	})
+
+	t.Run("unknown prefix", func(t *testing.T) {
+		fdb := fakeDB{columns: []string{"o.id", "id"}}
+		for _, err := range queries.QueryPair[order, user](ctx, fdb.open(t), "o.", "c.", "select ...") {
+			assert.Equal[E](t, err.Error(), "queries: the `id` column has neither the \"o.\" nor the \"c.\" prefix")
+		}
+	})
+}
@@ -0,0 +1,190 @@
+package queries
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"iter"
+)
+
+// Queryer is an interface implemented by [sql.DB], [sql.Tx] and [sql.Conn].
+type Queryer interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+}
+
+// Query executes the query and returns an iterator over the resulting rows scanned into T.
+// See [QueryOne] for how the rows are scanned.
+// The rows are closed once the iteration stops.
+// If ctx is canceled during the iteration, ctx.Err() is yielded and the iteration stops.
+func Query[T any](ctx context.Context, q Queryer, query string, args ...any) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+
+		rows, err := q.QueryContext(ctx, query, args...)
+		if err != nil {
+			yield(zero, fmt.Errorf("executing query %q: %w", query, err))
+			return
+		}
+		defer rows.Close()
+
+		columns, err := rows.Columns()
+		if err != nil {
+			yield(zero, fmt.Errorf("getting column names: %w", err))
+			return
+		}
+
+		s, err := compile[T](columns, scanOptions{fold: isFoldCase(q)})
+		if err != nil {
+			yield(zero, err)
+			return
+		}
+
+		for rows.Next() {
+			// the driver may have buffered rows that are returned even after ctx is canceled.
+			if err := ctx.Err(); err != nil {
+				yield(zero, err)
+				return
+			}
+			t, err := s.Scan(rows)
+			if err != nil {
+				yield(zero, err)
+				return
+			}
+			if !yield(t, nil) {
+				return
+			}
+		}
+		if err := rows.Err(); err != nil {
+			yield(zero, err)
+		}
+	}
+}
+
+// QueryIn splits the keys into chunks of the given size and executes the query for each chunk,
+// returning a single iterator over all the resulting rows.
+// The format must contain exactly one %+ verb, e.g. "select * from users where id in (%+$)",
+// which is expanded to the placeholders for the chunk (see [Builder.Appendf]).
+// It is useful to work around the limit on the number of placeholders supported by the database.
+func QueryIn[T, K any](ctx context.Context, q Queryer, format string, keys []K, chunk int) iter.Seq2[T, error] {
+	if chunk <= 0 {
+		panic("queries: chunk must be positive")
+	}
+
+	return func(yield func(T, error) bool) {
+		for start := 0; start < len(keys); start += chunk {
+			end := min(start+chunk, len(keys))
+
+			var qb Builder
+			qb.Appendf(format, keys[start:end])
+
+			for t, err := range Query[T](ctx, q, qb.String(), qb.Args...) {
+				if !yield(t, err) || err != nil {
+					return
+				}
+			}
+		}
+	}
+}
+
+// ErrTooManyRows is returned by [QueryOne] if the query yields more than one row.
+var ErrTooManyRows = errors.New("queries: too many rows")
+
+// QueryOne executes the query and scans the only resulting row into T.
+// If T is a struct, its fields are matched with the columns using the `sql` tags,
+// otherwise the query must return a single column.
+// Unlike [sql.DB.QueryRowContext], it returns [ErrTooManyRows] if the query yields more than one row.
+// If there are no rows, [sql.ErrNoRows] is returned.
+func QueryOne[T any](ctx context.Context, q Queryer, query string, args ...any) (T, error) {
+	var zero T
+
+	rows, err := q.QueryContext(ctx, query, args...)
+	if err != nil {
+		return zero, fmt.Errorf("executing query %q: %w", query, err)
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return zero, err
+		}
+		return zero, sql.ErrNoRows
+	}
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return zero, fmt.Errorf("getting column names: %w", err)
+	}
+
+	s, err := compile[T](columns, scanOptions{fold: isFoldCase(q)})
+	if err != nil {
+		return zero, err
+	}
+
+	t, err := s.Scan(rows)
+	if err != nil {
+		return zero, err
+	}
+	if rows.Next() {
+		return zero, ErrTooManyRows
+	}
+	if err := rows.Err(); err != nil {
+		return zero, err
+	}
+
+	return t, nil
+}
+
+// QueryMaybe is like [QueryOne], but instead of returning [sql.ErrNoRows] it reports whether the row was found.
+func QueryMaybe[T any](ctx context.Context, q Queryer, query string, args ...any) (T, bool, error) {
+	t, err := QueryOne[T](ctx, q, query, args...)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return t, false, nil
+	case err != nil:
+		return t, false, err
+	default:
+		return t, true, nil
+	}
+}
+
+// Row is the result of [QueryRow]. It allows chaining the query with one of the accessors,
+// e.g. in tests and scripts where an error is deliberately fatal:
+//
+//	n := queries.QueryRow[int](ctx, db, "select count(*) from users").Must()
+type Row[T any] struct {
+	value T
+	err   error
+}
+
+// QueryRow is like [QueryOne], but returns the result as a [Row].
+func QueryRow[T any](ctx context.Context, q Queryer, query string, args ...any) Row[T] {
+	t, err := QueryOne[T](ctx, q, query, args...)
+	return Row[T]{value: t, err: err}
+}
+
+// Value returns the scanned value and the error, as [QueryOne] does.
+func (r Row[T]) Value() (T, error) { return r.value, r.err }
+
+// Err returns the error, if any.
+func (r Row[T]) Err() error { return r.err }
+
+// Ptr returns a pointer to the scanned value, or nil if there are no rows, as [QueryMaybe] does.
+func (r Row[T]) Ptr() (*T, error) {
+	switch {
+	case errors.Is(r.err, sql.ErrNoRows):
+		return nil, nil
+	case r.err != nil:
+		return nil, r.err
+	default:
+		return &r.value, nil
+	}
+}
+
+// Must returns the scanned value or panics if there is an error, including [sql.ErrNoRows].
+func (r Row[T]) Must() T {
+	if r.err != nil {
+		panic(r.err)
+	}
+	return r.value
+}
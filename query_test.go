@@ -0,0 +1,194 @@
+package queries_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"testing"
+	"time"
+
+	"go-simpler.org/queries"
+	"go-simpler.org/queries/internal/assert"
+	. "go-simpler.org/queries/internal/assert/EF"
+)
+
+type user struct {
+	ID   int    `sql:"id"`
+	Name string `sql:"name"`
+}
+
+type customUser struct {
+	user
+	columns []string
+}
+
+func (u *customUser) ScanColumns(columns []string, scan func(...any) error) error {
+	u.columns = columns
+	return scan(&u.ID, &u.Name)
+}
+
+func TestQueryOne(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("struct", func(t *testing.T) {
+		fdb := fakeDB{columns: []string{"id", "name"}, rows: [][]driver.Value{{int64(1), "Alice"}}}
+		u, err := queries.QueryOne[user](ctx, fdb.open(t), "select id, name from users where id = $1", 1)
+		assert.NoErr[F](t, err)
+		assert.Equal[E](t, u, user{ID: 1, Name: "Alice"})
+		assert.Equal[E](t, fdb.args, [][]any{{int64(1)}})
+	})
+
+	t.Run("scalar", func(t *testing.T) {
+		fdb := fakeDB{columns: []string{"count"}, rows: [][]driver.Value{{int64(42)}}}
+		n, err := queries.QueryOne[int](ctx, fdb.open(t), "select count(*) from users")
+		assert.NoErr[F](t, err)
+		assert.Equal[E](t, n, 42)
+	})
+
+	t.Run("ordinal", func(t *testing.T) {
+		type stats struct {
+			queries.Ordinal
+			Count int
+			Label string `sql:"-"`
+			Avg   float64
+		}
+		fdb := fakeDB{columns: []string{"count(*)", "avg(age)"}, rows: [][]driver.Value{{int64(10), 33.5}}}
+		s, err := queries.QueryOne[stats](ctx, fdb.open(t), "select count(*), avg(age) from users")
+		assert.NoErr[F](t, err)
+		assert.Equal[E](t, s, stats{Count: 10, Avg: 33.5})
+	})
+
+	t.Run("json", func(t *testing.T) {
+		type event struct {
+			ID      int            `sql:"id"`
+			Payload map[string]int `sql:"payload,json"`
+		}
+		fdb := fakeDB{columns: []string{"id", "payload"}, rows: [][]driver.Value{{int64(1), []byte(`{"foo":1}`)}}}
+		e, err := queries.QueryOne[event](ctx, fdb.open(t), "select id, payload from events where id = $1", 1)
+		assert.NoErr[F](t, err)
+		assert.Equal[E](t, e, event{ID: 1, Payload: map[string]int{"foo": 1}})
+	})
+
+	t.Run("layout", func(t *testing.T) {
+		type event struct {
+			ID   int       `sql:"id"`
+			Date time.Time `sql:"date,layout=2006-01-02"`
+		}
+		fdb := fakeDB{columns: []string{"id", "date"}, rows: [][]driver.Value{{int64(1), "2024-01-02"}}}
+		e, err := queries.QueryOne[event](ctx, fdb.open(t), "select id, date from events where id = $1", 1)
+		assert.NoErr[F](t, err)
+		assert.Equal[E](t, e, event{ID: 1, Date: time.Date(2024, time.January, 2, 0, 0, 0, 0, time.UTC)})
+	})
+
+	t.Run("column scanner", func(t *testing.T) {
+		fdb := fakeDB{columns: []string{"id", "name"}, rows: [][]driver.Value{{int64(1), "Alice"}}}
+		u, err := queries.QueryOne[customUser](ctx, fdb.open(t), "select id, name from users where id = $1", 1)
+		assert.NoErr[F](t, err)
+		assert.Equal[E](t, u, customUser{user: user{ID: 1, Name: "Alice"}, columns: []string{"id", "name"}})
+	})
+
+	t.Run("no rows", func(t *testing.T) {
+		fdb := fakeDB{columns: []string{"id", "name"}}
+		_, err := queries.QueryOne[user](ctx, fdb.open(t), "select id, name from users")
+		assert.IsErr[E](t, err, sql.ErrNoRows)
+	})
+
+	t.Run("too many rows", func(t *testing.T) {
+		fdb := fakeDB{columns: []string{"id", "name"}, rows: [][]driver.Value{{int64(1), "Alice"}, {int64(2), "Bob"}}}
+		_, err := queries.QueryOne[user](ctx, fdb.open(t), "select id, name from users")
+		assert.IsErr[E](t, err, queries.ErrTooManyRows)
+	})
+}
+
+func TestQueryMaybe(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("found", func(t *testing.T) {
+		fdb := fakeDB{columns: []string{"id", "name"}, rows: [][]driver.Value{{int64(1), "Alice"}}}
+		u, found, err := queries.QueryMaybe[user](ctx, fdb.open(t), "select id, name from users where id = $1", 1)
+		assert.NoErr[F](t, err)
+		assert.Equal[E](t, found, true)
+		assert.Equal[E](t, u, user{ID: 1, Name: "Alice"})
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		fdb := fakeDB{columns: []string{"id", "name"}}
+		_, found, err := queries.QueryMaybe[user](ctx, fdb.open(t), "select id, name from users where id = $1", 1)
+		assert.NoErr[F](t, err)
+		assert.Equal[E](t, found, false)
+	})
+}
+
+func TestQueryRow(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("found", func(t *testing.T) {
+		fdb := fakeDB{columns: []string{"id", "name"}, rows: [][]driver.Value{{int64(1), "Alice"}}}
+		row := queries.QueryRow[user](ctx, fdb.open(t), "select id, name from users where id = $1", 1)
+		assert.Equal[E](t, row.Must(), user{ID: 1, Name: "Alice"})
+
+		u, err := row.Ptr()
+		assert.NoErr[F](t, err)
+		assert.Equal[E](t, *u, user{ID: 1, Name: "Alice"})
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		fdb := fakeDB{columns: []string{"id", "name"}}
+		row := queries.QueryRow[user](ctx, fdb.open(t), "select id, name from users where id = $1", 1)
+		assert.IsErr[E](t, row.Err(), sql.ErrNoRows)
+
+		u, err := row.Ptr()
+		assert.NoErr[F](t, err)
+		assert.Equal[E](t, u, (*user)(nil))
+		assert.Panics[E](t, func() { row.Must() }, sql.ErrNoRows)
+	})
+}
+
+func TestQuery(t *testing.T) {
+	ctx := context.Background()
+	fdb := fakeDB{columns: []string{"id", "name"}, rows: [][]driver.Value{{int64(1), "Alice"}, {int64(2), "Bob"}}}
+
+	var users []user
+	for u, err := range queries.Query[user](ctx, fdb.open(t), "select id, name from users") {
+		assert.NoErr[F](t, err)
+		users = append(users, u)
+	}
+	assert.Equal[E](t, users, []user{{ID: 1, Name: "Alice"}, {ID: 2, Name: "Bob"}})
+}
+
+func TestQuery_canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	fdb := fakeDB{columns: []string{"id", "name"}, rows: [][]driver.Value{{int64(1), "Alice"}, {int64(2), "Bob"}}}
+
+	var users []user
+	var err error
+	for u, e := range queries.Query[user](ctx, fdb.open(t), "select id, name from users") {
+		if e != nil {
+			err = e
+			break
+		}
+		users = append(users, u)
+		cancel()
+	}
+	assert.IsErr[E](t, err, context.Canceled)
+	assert.Equal[E](t, users, []user{{ID: 1, Name: "Alice"}})
+}
+
+func TestQueryIn(t *testing.T) {
+	ctx := context.Background()
+	fdb := fakeDB{columns: []string{"id", "name"}, rows: [][]driver.Value{{int64(1), "Alice"}}}
+
+	var n int
+	for _, err := range queries.QueryIn[user](ctx, fdb.open(t), "select id, name from users where id in (%+$)", []int{1, 2, 3, 4, 5}, 2) {
+		assert.NoErr[F](t, err)
+		n++
+	}
+	assert.Equal[E](t, n, 3)
+	assert.Equal[E](t, fdb.queries, []string{
+		"select id, name from users where id in ($1, $2)",
+		"select id, name from users where id in ($1, $2)",
+		"select id, name from users where id in ($1)",
+	})
+	assert.Equal[E](t, fdb.args, [][]any{{int64(1), int64(2)}, {int64(3), int64(4)}, {int64(5)}})
+}
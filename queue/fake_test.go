@@ -0,0 +1,102 @@
+package queue_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeDB is a minimal [driver.Connector] backed by a single table: every SELECT returns the selected columns
+// ("*" for all) of all its rows, every other query affects the given number of rows.
+// The executed queries are recorded along with their arguments.
+type fakeDB struct {
+	columns      []string
+	rows         [][]driver.Value
+	rowsAffected int64
+
+	mu      sync.Mutex
+	queries []string
+	args    [][]any
+}
+
+func (f *fakeDB) open(t *testing.T) *sql.DB {
+	t.Helper()
+	db := sql.OpenDB(f)
+	t.Cleanup(func() { _ = db.Close() })
+	return db
+}
+
+func (f *fakeDB) Connect(context.Context) (driver.Conn, error) { return fakeConn{f}, nil }
+func (f *fakeDB) Driver() driver.Driver                        { return nil }
+
+func (f *fakeDB) record(query string, args []driver.NamedValue) {
+	a := make([]any, len(args))
+	for i, arg := range args {
+		a[i] = arg.Value
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.queries = append(f.queries, query)
+	f.args = append(f.args, a)
+}
+
+type fakeConn struct{ db *fakeDB }
+
+func (fakeConn) Prepare(string) (driver.Stmt, error) { return nil, driver.ErrSkip }
+func (fakeConn) Close() error                        { return nil }
+func (fakeConn) Begin() (driver.Tx, error)           { return fakeTx{}, nil }
+
+func (c fakeConn) QueryContext(_ context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	c.db.record(query, args)
+
+	selected, _, ok := strings.Cut(strings.TrimPrefix(query, "select "), " from ")
+	if !ok || selected == "*" {
+		return &fakeRows{columns: c.db.columns, rows: c.db.rows}, nil
+	}
+
+	columns := strings.Split(selected, ", ")
+	rows := make([][]driver.Value, len(c.db.rows))
+	for i, row := range c.db.rows {
+		for _, column := range columns {
+			j := slices.Index(c.db.columns, column)
+			if j == -1 {
+				return nil, fmt.Errorf("no such column: %s", column)
+			}
+			rows[i] = append(rows[i], row[j])
+		}
+	}
+	return &fakeRows{columns: columns, rows: rows}, nil
+}
+
+func (c fakeConn) ExecContext(_ context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	c.db.record(query, args)
+	return driver.RowsAffected(c.db.rowsAffected), nil
+}
+
+type fakeTx struct{}
+
+func (fakeTx) Commit() error   { return nil }
+func (fakeTx) Rollback() error { return nil }
+
+type fakeRows struct {
+	columns []string
+	rows    [][]driver.Value
+}
+
+func (r *fakeRows) Columns() []string { return r.columns }
+func (r *fakeRows) Close() error      { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+	return nil
+}
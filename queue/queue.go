@@ -0,0 +1,137 @@
+// Package queue implements a work queue on top of a database table
+// using the SELECT ... FOR UPDATE SKIP LOCKED pattern (PostgreSQL and MySQL 8.0+).
+//
+// The table must have the following columns (other columns are allowed):
+//
+//	id           primary key
+//	status       text: [StatusPending], [StatusRunning] or [StatusDone]
+//	locked_until timestamp: the lease deadline of a running job
+//	claim_token  text: the [Token] of the claim that is running the job
+package queue
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"go-simpler.org/queries"
+)
+
+// Job statuses.
+const (
+	StatusPending = "pending"
+	StatusRunning = "running"
+	StatusDone    = "done"
+)
+
+// Token identifies a claim. It must be passed to [Queue.Heartbeat] and [Queue.Complete],
+// so that a worker whose lease has expired cannot affect the job once it is claimed by another worker.
+type Token string
+
+// ErrLeaseLost is returned by [Queue.Heartbeat] and [Queue.Complete] if the job is no longer running,
+// e.g. because its lease has expired and the job has been claimed by another worker.
+var ErrLeaseLost = errors.New("queue: lease lost")
+
+// Queue is a work queue backed by a database table.
+type Queue struct {
+	DB      *sql.DB
+	Table   string
+	Dialect queries.Dialect // either PostgreSQL or MySQL.
+}
+
+// Claim locks up to n jobs that are either pending or whose lease has expired,
+// marks them as running for the lease duration, and returns them scanned into T along with the claim token.
+// Only the columns of T (see [queries.Columns]) are selected, so T does not need fields for the queue columns.
+// Jobs locked by other workers are skipped, so multiple workers can claim jobs concurrently.
+func Claim[T any](ctx context.Context, q *Queue, n int, lease time.Duration) (_ []T, _ Token, err error) {
+	now := time.Now()
+	token := newToken()
+
+	tx, err := q.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, "", fmt.Errorf("starting transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	qb := queries.Builder{Dialect: q.Dialect}
+	qb.Appendf("select id from %s where status = %P or (status = %P and locked_until < %P) order by id limit %P",
+		q.Table, StatusPending, StatusRunning, now, n)
+	qb.AppendLock(queries.ForUpdateSkipLocked)
+
+	var ids []any
+	for id, err := range queries.Query[any](ctx, tx, qb.String(), qb.Args...) {
+		if err != nil {
+			return nil, "", err
+		}
+		ids = append(ids, id)
+	}
+	if len(ids) == 0 {
+		return nil, "", tx.Commit()
+	}
+
+	qb = queries.Builder{Dialect: q.Dialect}
+	qb.Appendf("update %s set status = %P, locked_until = %P, claim_token = %P where id in (%+P)",
+		q.Table, StatusRunning, now.Add(lease), string(token), ids)
+	if _, err := queries.Exec(ctx, tx, qb.String(), qb.Args...); err != nil {
+		return nil, "", err
+	}
+
+	qb = queries.Builder{Dialect: q.Dialect}
+	qb.Appendf("select %s from %s where id in (%+P) order by id", strings.Join(queries.Columns[T](), ", "), q.Table, ids)
+
+	var jobs []T
+	for job, err := range queries.Query[T](ctx, tx, qb.String(), qb.Args...) {
+		if err != nil {
+			return nil, "", err
+		}
+		jobs = append(jobs, job)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, "", fmt.Errorf("committing transaction: %w", err)
+	}
+
+	return jobs, token, nil
+}
+
+// Heartbeat extends the lease of the job running under the claim token.
+func (q *Queue) Heartbeat(ctx context.Context, id any, token Token, lease time.Duration) error {
+	qb := queries.Builder{Dialect: q.Dialect}
+	qb.Appendf("update %s set locked_until = %P where id = %P and status = %P and claim_token = %P",
+		q.Table, time.Now().Add(lease), id, StatusRunning, string(token))
+	return q.exec(ctx, &qb)
+}
+
+// Complete marks the job running under the claim token as done.
+func (q *Queue) Complete(ctx context.Context, id any, token Token) error {
+	qb := queries.Builder{Dialect: q.Dialect}
+	qb.Appendf("update %s set status = %P where id = %P and status = %P and claim_token = %P",
+		q.Table, StatusDone, id, StatusRunning, string(token))
+	return q.exec(ctx, &qb)
+}
+
+func (q *Queue) exec(ctx context.Context, qb *queries.Builder) error {
+	res, err := queries.Exec(ctx, q.DB, qb.String(), qb.Args...)
+	if err != nil {
+		return err
+	}
+	if res.RowsAffected == 0 {
+		return ErrLeaseLost
+	}
+	return nil
+}
+
+func newToken() Token {
+	var b [16]byte
+	_, _ = rand.Read(b[:]) // never returns an error.
+	return Token(hex.EncodeToString(b[:]))
+}
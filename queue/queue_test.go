@@ -0,0 +1,93 @@
+package queue_test
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"testing"
+	"time"
+
+	"go-simpler.org/queries"
+	"go-simpler.org/queries/internal/assert"
+	. "go-simpler.org/queries/internal/assert/EF"
+	"go-simpler.org/queries/queue"
+)
+
+type job struct {
+	ID      int    `sql:"id"`
+	Payload string `sql:"payload"`
+}
+
+// jobsTable returns the columns of the jobs table and its rows with the given ids.
+func jobsTable(ids ...int64) ([]string, [][]driver.Value) {
+	columns := []string{"id", "status", "locked_until", "claim_token", "payload"}
+	var rows [][]driver.Value
+	for _, id := range ids {
+		rows = append(rows, []driver.Value{id, queue.StatusPending, nil, nil, fmt.Sprintf("job %d", id)})
+	}
+	return columns, rows
+}
+
+func TestClaim(t *testing.T) {
+	ctx := context.Background()
+	var fdb fakeDB
+	fdb.columns, fdb.rows = jobsTable(1, 2)
+	q := queue.Queue{DB: fdb.open(t), Table: "jobs", Dialect: queries.PostgreSQL}
+
+	jobs, token, err := queue.Claim[job](ctx, &q, 2, time.Minute)
+	assert.NoErr[F](t, err)
+	assert.Equal[E](t, jobs, []job{{ID: 1, Payload: "job 1"}, {ID: 2, Payload: "job 2"}})
+	assert.Equal[E](t, len(token), 32)
+
+	assert.Equal[E](t, fdb.queries, []string{
+		"select id from jobs where status = $1 or (status = $2 and locked_until < $3) order by id limit $4 for update skip locked",
+		"update jobs set status = $1, locked_until = $2, claim_token = $3 where id in ($4, $5)",
+		"select id, payload from jobs where id in ($1, $2) order by id",
+	})
+	assert.Equal[E](t, fdb.args[1][2], any(string(token)))
+
+	_, other, err := queue.Claim[job](ctx, &q, 2, time.Minute)
+	assert.NoErr[F](t, err)
+	assert.Equal[E](t, other != token, true)
+}
+
+func TestClaim_empty(t *testing.T) {
+	ctx := context.Background()
+	var fdb fakeDB
+	fdb.columns, _ = jobsTable()
+	q := queue.Queue{DB: fdb.open(t), Table: "jobs", Dialect: queries.PostgreSQL}
+
+	jobs, _, err := queue.Claim[job](ctx, &q, 2, time.Minute)
+	assert.NoErr[F](t, err)
+	assert.Equal[E](t, len(jobs), 0)
+	assert.Equal[E](t, len(fdb.queries), 1)
+}
+
+func TestQueue_Heartbeat(t *testing.T) {
+	ctx := context.Background()
+	fdb := fakeDB{rowsAffected: 1}
+	q := queue.Queue{DB: fdb.open(t), Table: "jobs", Dialect: queries.PostgreSQL}
+
+	assert.NoErr[F](t, q.Heartbeat(ctx, 1, "token", time.Minute))
+	assert.Equal[E](t, fdb.queries, []string{"update jobs set locked_until = $1 where id = $2 and status = $3 and claim_token = $4"})
+	assert.Equal[E](t, fdb.args[0][1:], []any{int64(1), queue.StatusRunning, "token"})
+}
+
+func TestQueue_Complete(t *testing.T) {
+	ctx := context.Background()
+	fdb := fakeDB{rowsAffected: 1}
+	q := queue.Queue{DB: fdb.open(t), Table: "jobs", Dialect: queries.PostgreSQL}
+
+	assert.NoErr[F](t, q.Complete(ctx, 1, "token"))
+	assert.Equal[E](t, fdb.queries, []string{"update jobs set status = $1 where id = $2 and status = $3 and claim_token = $4"})
+	assert.Equal[E](t, fdb.args, [][]any{{queue.StatusDone, int64(1), queue.StatusRunning, "token"}})
+}
+
+func TestQueue_leaseLost(t *testing.T) {
+	ctx := context.Background()
+	fdb := fakeDB{rowsAffected: 0} // the job has been claimed by another worker with a different token.
+	q := queue.Queue{DB: fdb.open(t), Table: "jobs", Dialect: queries.PostgreSQL}
+
+	assert.IsErr[E](t, q.Heartbeat(ctx, 1, "stale", time.Minute), queue.ErrLeaseLost)
+	assert.IsErr[E](t, q.Complete(ctx, 1, "stale"), queue.ErrLeaseLost)
+}
@@ -0,0 +1,73 @@
+package queries
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+	"time"
+)
+
+// Record is a query executed through a [Recorder].
+type Record struct {
+	Query    string
+	Args     []any
+	Start    time.Time
+	Duration time.Duration // for QueryContext, the time until the first rows are available, not until they are read.
+	Err      error
+}
+
+// Recorder wraps a [QueryExecer] and records the most recent queries in a ring buffer,
+// so that e.g. a crash handler or a debug endpoint can dump the recent database activity.
+// It can be passed to the package helpers, such as [Query] and [Exec], in place of the wrapped value.
+// It is safe for concurrent use.
+type Recorder struct {
+	qe QueryExecer
+
+	mu      sync.Mutex
+	records []Record
+	next    int // the index of the next record to overwrite once the buffer is full.
+}
+
+// NewRecorder returns a [Recorder] that keeps the n most recent queries.
+func NewRecorder(qe QueryExecer, n int) *Recorder {
+	if n <= 0 {
+		panic("queries: n must be positive")
+	}
+	return &Recorder{qe: qe, records: make([]Record, 0, n)}
+}
+
+// QueryContext implements the [Queryer] interface.
+func (r *Recorder) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	start := time.Now()
+	rows, err := r.qe.QueryContext(ctx, query, args...)
+	r.record(Record{Query: query, Args: args, Start: start, Duration: time.Since(start), Err: err})
+	return rows, err
+}
+
+// ExecContext implements the [Execer] interface.
+func (r *Recorder) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	start := time.Now()
+	res, err := r.qe.ExecContext(ctx, query, args...)
+	r.record(Record{Query: query, Args: args, Start: start, Duration: time.Since(start), Err: err})
+	return res, err
+}
+
+func (r *Recorder) record(rec Record) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.records) < cap(r.records) {
+		r.records = append(r.records, rec)
+		return
+	}
+	r.records[r.next] = rec
+	r.next = (r.next + 1) % len(r.records)
+}
+
+// Recent returns the recorded queries from the oldest to the most recent one.
+func (r *Recorder) Recent() []Record {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	recent := make([]Record, 0, len(r.records))
+	recent = append(recent, r.records[r.next:]...)
+	return append(recent, r.records[:r.next]...)
+}
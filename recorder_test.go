@@ -0,0 +1,30 @@
+package queries_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go-simpler.org/queries"
+	"go-simpler.org/queries/internal/assert"
+	. "go-simpler.org/queries/internal/assert/EF"
+)
+
+func TestRecorder(t *testing.T) {
+	ctx := context.Background()
+	errDB := errors.New("database error")
+
+	fdb := fakeDB{err: errDB}
+	r := queries.NewRecorder(fdb.open(t), 2)
+
+	_, _ = r.ExecContext(ctx, "delete from users where id = ?", 1)
+	_, _ = r.ExecContext(ctx, "delete from users where id = ?", 2)
+	_, _ = r.QueryContext(ctx, "select * from users")
+
+	recent := r.Recent()
+	assert.Equal[F](t, len(recent), 2)
+	assert.Equal[E](t, recent[0].Query, "delete from users where id = ?")
+	assert.Equal[E](t, recent[0].Args, []any{2})
+	assert.Equal[E](t, recent[1].Query, "select * from users")
+	assert.IsErr[E](t, recent[1].Err, errDB)
+}
@@ -0,0 +1,64 @@
+package queries
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// Savepoint creates the savepoint within the transaction tx and calls fn.
+// If fn returns an error, the transaction is rolled back to the savepoint, undoing only the changes made by fn,
+// and the error is returned; otherwise the savepoint is released (MSSQL and Oracle do not support releasing savepoints).
+// The name must consist of ASCII letters, digits and underscores.
+func Savepoint(ctx context.Context, tx Execer, d Dialect, name string, fn func() error) error {
+	if !isPlainIdent(name) {
+		panic(fmt.Sprintf("queries: bad savepoint name %q", name))
+	}
+
+	var create, release, rollback string
+	if d == MSSQL {
+		create = "save transaction " + name
+		rollback = "rollback transaction " + name
+	} else {
+		create = "savepoint " + name
+		rollback = "rollback to savepoint " + name
+	}
+	if d.Info().ReleaseSavepoint {
+		release = "release savepoint " + name
+	}
+
+	if _, err := tx.ExecContext(ctx, create); err != nil {
+		return fmt.Errorf("creating savepoint: %w", err)
+	}
+
+	if err := fn(); err != nil {
+		if _, rbErr := tx.ExecContext(ctx, rollback); rbErr != nil {
+			return errors.Join(err, fmt.Errorf("rolling back to savepoint: %w", rbErr))
+		}
+		return err
+	}
+
+	if release != "" {
+		if _, err := tx.ExecContext(ctx, release); err != nil {
+			return fmt.Errorf("releasing savepoint: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// isPlainIdent reports whether s is a non-empty identifier consisting of ASCII letters, digits and underscores
+// that does not start with a digit, i.e. it is safe to use in a query without quoting.
+func isPlainIdent(s string) bool {
+	if s == "" || (s[0] >= '0' && s[0] <= '9') {
+		return false
+	}
+	for _, r := range s {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_':
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,37 @@
+package queries_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go-simpler.org/queries"
+	"go-simpler.org/queries/internal/assert"
+	. "go-simpler.org/queries/internal/assert/EF"
+)
+
+func TestSavepoint(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("release", func(t *testing.T) {
+		fdb := fakeDB{}
+		err := queries.Savepoint(ctx, fdb.open(t), queries.PostgreSQL, "sp1", func() error { return nil })
+		assert.NoErr[F](t, err)
+		assert.Equal[E](t, fdb.queries, []string{"savepoint sp1", "release savepoint sp1"})
+	})
+
+	t.Run("rollback", func(t *testing.T) {
+		fdb := fakeDB{}
+		errFn := errors.New("fn error")
+		err := queries.Savepoint(ctx, fdb.open(t), queries.MSSQL, "sp1", func() error { return errFn })
+		assert.IsErr[E](t, err, errFn)
+		assert.Equal[E](t, fdb.queries, []string{"save transaction sp1", "rollback transaction sp1"})
+	})
+
+	t.Run("bad name", func(t *testing.T) {
+		fdb := fakeDB{}
+		assert.Panics[E](t, func() {
+			_ = queries.Savepoint(ctx, fdb.open(t), queries.PostgreSQL, "sp; drop table users", func() error { return nil })
+		}, `queries: bad savepoint name "sp; drop table users"`)
+	})
+}
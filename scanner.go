@@ -1,13 +1,17 @@
 package queries
 
 import (
+	"cmp"
+	"database/sql"
 	"errors"
 	"fmt"
+	"iter"
 	"reflect"
+	"strings"
+	"time"
 )
 
-// TODO: consider merging ScanOne() + ScanAll() -> Scan().
-
+// Rows is the interface of [sql.Rows] used for scanning.
 type Rows interface {
 	Scan(...any) error
 	Columns() ([]string, error)
@@ -15,38 +19,97 @@ type Rows interface {
 	Err() error
 }
 
+// ScanOption is an option for [ScanRows] and [Compile].
+type ScanOption func(*scanOptions)
+
+type scanOptions struct {
+	tag           string              // the struct tag with the column names; "sql" if empty.
+	ignoreUnknown bool                // columns with no field are discarded.
+	mapper        func(string) string // applied to the column names before matching.
+	fold          bool                // columns with no field are matched in lower case (see FoldCase).
+}
+
+// WithTag makes the fields be matched with the columns using the given struct tag instead of `sql`.
+// The tag options are the same.
+func WithTag(name string) ScanOption {
+	return func(o *scanOptions) { o.tag = name }
+}
+
+// IgnoreUnknown makes columns with no matching field be discarded instead of reporting an error.
+func IgnoreUnknown() ScanOption {
+	return func(o *scanOptions) { o.ignoreUnknown = true }
+}
+
+// MapColumns applies the mapper (e.g. [strings.ToLower]) to the column names before matching them with the fields.
+func MapColumns(mapper func(column string) string) ScanOption {
+	return func(o *scanOptions) { o.mapper = mapper }
+}
+
+// ScanRows returns an iterator over the rows scanned into T, as [Query] does for the rows it gets.
+// It is useful for rows obtained elsewhere, e.g. from a driver-specific API.
+// The rows are not closed, it is the responsibility of the caller.
+func ScanRows[T any](rows Rows, opts ...ScanOption) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+
+		columns, err := rows.Columns()
+		if err != nil {
+			yield(zero, fmt.Errorf("getting column names: %w", err))
+			return
+		}
+
+		s, err := Compile[T](columns, opts...)
+		if err != nil {
+			yield(zero, err)
+			return
+		}
+
+		for rows.Next() {
+			t, err := s.Scan(rows)
+			if err != nil {
+				yield(zero, err)
+				return
+			}
+			if !yield(t, nil) {
+				return
+			}
+		}
+		if err := rows.Err(); err != nil {
+			yield(zero, err)
+		}
+	}
+}
+
+// ScanOne scans the first row into dst, which must be a non-nil struct pointer.
+// It uses the same rules as [ScanRows].
 func ScanOne(dst any, rows Rows) error {
 	v := reflect.ValueOf(dst)
 	if !v.IsValid() || v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct || v.IsNil() {
 		panic("queries: dst must be a non-nil struct pointer")
 	}
 
-	fields := parseStruct(v.Elem())
-
 	columns, err := rows.Columns()
 	if err != nil {
 		return fmt.Errorf("getting column names: %w", err)
 	}
 
-	target := make([]any, len(columns))
-	for i, column := range columns {
-		field, ok := fields[column]
-		if !ok {
-			panic(fmt.Sprintf("queries: no field for the %#q column", column))
-		}
-		target[i] = field
+	s, err := newScanner(v.Type().Elem(), columns, scanOptions{})
+	if err != nil {
+		return err
 	}
 
 	if !rows.Next() {
 		return errors.New("queries: no rows to scan")
 	}
-	if err := rows.Scan(target...); err != nil {
-		return fmt.Errorf("scanning rows: %w", err)
+	if err := s.scan(v, rows); err != nil {
+		return err
 	}
 
 	return rows.Err()
 }
 
+// ScanAll appends all the rows to dst, which must be a pointer to a slice of structs.
+// It uses the same rules as [ScanRows].
 func ScanAll(dst any, rows Rows) error {
 	v := reflect.ValueOf(dst)
 	if !v.IsValid() || v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Slice || v.Elem().Type().Elem().Kind() != reflect.Struct {
@@ -55,53 +118,305 @@ func ScanAll(dst any, rows Rows) error {
 
 	slice := v.Elem()
 	typ := slice.Type().Elem()
-	elem := reflect.New(typ).Elem()
-	fields := parseStruct(elem)
 
 	columns, err := rows.Columns()
 	if err != nil {
 		return fmt.Errorf("getting column names: %w", err)
 	}
 
-	target := make([]any, len(columns))
-	for i, column := range columns {
-		field, ok := fields[column]
-		if !ok {
-			panic(fmt.Sprintf("queries: no field for the %#q column", column))
-		}
-		target[i] = field
+	s, err := newScanner(typ, columns, scanOptions{})
+	if err != nil {
+		return err
 	}
 
 	for rows.Next() {
-		if err := rows.Scan(target...); err != nil {
-			return fmt.Errorf("scanning rows: %w", err)
+		elem := reflect.New(typ)
+		if err := s.scan(elem, rows); err != nil {
+			return err
 		}
-		slice.Set(reflect.Append(slice, elem))
+		slice.Set(reflect.Append(slice, elem.Elem()))
 	}
 
 	return rows.Err()
 }
 
+// ColumnScanner is implemented by types that scan the columns of a row themselves, without reflection.
+// The implementation is usually generated by the go-simpler.org/queries/cmd/queries command.
+type ColumnScanner interface {
+	ScanColumns(columns []string, scan func(...any) error) error
+}
+
+// Scanner scans rows into values of type T.
+// Use [Compile] to create one.
+type Scanner[T any] struct{ s *scanner }
+
+// Compile resolves the struct fields of T for the given columns once,
+// so that rows with these columns can be scanned without parsing T for each of them.
+// If T is not a struct (or is a struct that can be scanned directly, e.g. [time.Time]),
+// it is scanned as a single column.
+// If T embeds [Ordinal], its fields are matched with the columns by position instead of the `sql` tags.
+// If *T implements the [ColumnScanner] interface, its ScanColumns method is used instead.
+func Compile[T any](columns []string, opts ...ScanOption) (*Scanner[T], error) {
+	var o scanOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return compile[T](columns, o)
+}
+
+func compile[T any](columns []string, o scanOptions) (*Scanner[T], error) {
+	s, err := newScanner(reflect.TypeFor[T](), columns, o)
+	if err != nil {
+		return nil, err
+	}
+	return &Scanner[T]{s: s}, nil
+}
+
+// Scan scans the current row into a new value of type T.
+func (s *Scanner[T]) Scan(rows Rows) (T, error) {
+	var t T
+	if err := s.s.scan(reflect.ValueOf(&t), rows); err != nil {
+		var zero T
+		return zero, err
+	}
+	return t, nil
+}
+
+// scanner is the scanning engine shared by [Scanner] and the untyped [ScanOne] and [ScanAll].
+type scanner struct {
+	columns []string
+	fields  []field // the struct field for each column.
+	single  bool    // the type is not a struct and is scanned as a single column.
+	custom  bool    // the pointer to the type implements the ColumnScanner interface.
+}
+
+func newScanner(typ reflect.Type, columns []string, o scanOptions) (*scanner, error) {
+	if o.mapper != nil {
+		columns = mapColumns(columns, o.mapper)
+	}
+
+	if reflect.PointerTo(typ).Implements(columnScannerType) {
+		if o.fold {
+			columns = mapColumns(columns, strings.ToLower)
+		}
+		return &scanner{columns: columns, custom: true}, nil
+	}
+
+	if !isStruct(typ) {
+		return &scanner{single: true}, nil
+	}
+
+	fields, err := columnFields(typ, columns, o)
+	if err != nil {
+		return nil, err
+	}
+
+	return &scanner{fields: fields}, nil
+}
+
+// scan scans the current row into the value ptr points to.
+func (s *scanner) scan(ptr reflect.Value, rows Rows) error {
+	if s.custom {
+		if err := ptr.Interface().(ColumnScanner).ScanColumns(s.columns, rows.Scan); err != nil {
+			return fmt.Errorf("scanning rows: %w", err)
+		}
+		return nil
+	}
+
+	var target []any
+	if s.single {
+		target = []any{ptr.Interface()}
+	} else {
+		v := ptr.Elem()
+		target = make([]any, len(s.fields))
+		for i, f := range s.fields {
+			target[i] = f.target(v)
+		}
+	}
+
+	if err := rows.Scan(target...); err != nil {
+		return fmt.Errorf("scanning rows: %w", err)
+	}
+
+	return nil
+}
+
+var (
+	scannerType       = reflect.TypeFor[sql.Scanner]()
+	columnScannerType = reflect.TypeFor[ColumnScanner]()
+	timeType          = reflect.TypeFor[time.Time]()
+)
+
+func mapColumns(columns []string, mapper func(string) string) []string {
+	mapped := make([]string, len(columns))
+	for i, column := range columns {
+		mapped[i] = mapper(column)
+	}
+	return mapped
+}
+
+// isStruct reports whether values of the type should be scanned field by field.
+// Structs that can be scanned directly (e.g. [time.Time] or [sql.NullString]) are not included.
+func isStruct(typ reflect.Type) bool {
+	return typ.Kind() == reflect.Struct && typ != timeType && !reflect.PointerTo(typ).Implements(scannerType)
+}
+
+// Ordinal can be embedded in a struct to scan its exported fields (except the ones tagged with `sql:"-"`)
+// in declaration order, ignoring the column names.
+// It is useful when the columns cannot be named after the `sql` tags, e.g. for computed expressions.
+// The number of columns must match the number of fields.
+type Ordinal struct{}
+
+func isOrdinal(typ reflect.Type) bool {
+	for i := 0; i < typ.NumField(); i++ {
+		if sf := typ.Field(i); sf.Anonymous && sf.Type == reflect.TypeFor[Ordinal]() {
+			return true
+		}
+	}
+	return false
+}
+
+// columnFields returns the struct field for each of the columns.
+func columnFields(typ reflect.Type, columns []string, o scanOptions) ([]field, error) {
+	if isOrdinal(typ) {
+		var fields []field
+		for i := 0; i < typ.NumField(); i++ {
+			if sf := typ.Field(i); sf.IsExported() && !sf.Anonymous && sf.Tag.Get(cmp.Or(o.tag, "sql")) != "-" {
+				fields = append(fields, field{index: i})
+			}
+		}
+		if len(fields) != len(columns) {
+			return nil, fmt.Errorf("queries: %s has %d fields, but there are %d columns", typ, len(fields), len(columns))
+		}
+		return fields, nil
+	}
+
+	structFields := make(map[string]field)
+	for _, f := range parseStructTag(typ, cmp.Or(o.tag, "sql")) {
+		structFields[f.column] = f
+	}
+
+	fields := make([]field, len(columns))
+	for i, column := range columns {
+		f, ok := structFields[column]
+		if !ok && o.fold {
+			f, ok = structFields[strings.ToLower(column)]
+		}
+		if !ok && o.ignoreUnknown {
+			f, ok = field{column: column, index: -1}, true
+		}
+		if !ok {
+			return nil, fmt.Errorf("queries: no field for the %#q column", column)
+		}
+		fields[i] = f
+	}
+	return fields, nil
+}
+
+// field is a struct field tagged with `sql`.
+type field struct {
+	column   string
+	index    int    // -1 if the column is discarded.
+	json     bool   // the `json` option: the column contains JSON.
+	layout   string // the `layout` option: the column contains time as text in this layout.
+	omitZero bool   // the `omitzero` option: the field is not written if it has the zero value.
+	inOut    bool   // the `inout` option: the output parameter is also passed as input.
+	pk       bool   // the `pk` option: the column is (a part of) the primary key.
+	version  bool   // the `version` option: the column is a row version for optimistic locking.
+}
+
+// target returns the scan destination for the field of the struct v.
+func (f field) target(v reflect.Value) any {
+	if f.index < 0 {
+		return new(any)
+	}
+	ptr := v.Field(f.index).Addr().Interface()
+	switch {
+	case f.json:
+		return JSON{V: ptr}
+	case f.layout != "":
+		return Time{T: ptr.(*time.Time), Layout: f.layout}
+	default:
+		return ptr
+	}
+}
+
+// value returns the field of the struct v as a query argument.
+func (f field) value(v reflect.Value) any {
+	value := v.Field(f.index).Interface()
+	switch {
+	case f.json:
+		return JSON{V: value}
+	case f.layout != "":
+		t := value.(time.Time)
+		return Time{T: &t, Layout: f.layout}
+	default:
+		return value
+	}
+}
+
+// parseStruct returns the fields tagged with `sql` in declaration order.
 // TODO: support nested structs.
-func parseStruct(v reflect.Value) map[string]any {
-	fields := make(map[string]any, v.NumField())
+func parseStruct(typ reflect.Type) []field {
+	return parseStructTag(typ, "sql")
+}
+
+// parseStructTag is [parseStruct] with a custom tag name.
+func parseStructTag(typ reflect.Type, tagName string) []field {
+	var fields []field
 
-	for i := 0; i < v.NumField(); i++ {
-		field := v.Field(i)
-		if !field.CanSet() {
+	for i := 0; i < typ.NumField(); i++ {
+		sf := typ.Field(i)
+		if !sf.IsExported() {
 			continue
 		}
 
-		sf := v.Type().Field(i)
-		name, ok := sf.Tag.Lookup("sql")
-		if !ok {
+		tag, ok := sf.Tag.Lookup(tagName)
+		if !ok || tag == "-" {
 			continue
 		}
+
+		name, options, _ := strings.Cut(tag, ",")
 		if name == "" {
-			panic(fmt.Sprintf("queries: %s field has an empty `sql` tag", sf.Name))
+			panic(fmt.Sprintf("queries: %s field has an empty `%s` tag", sf.Name, tagName))
+		}
+
+		f := field{column: name, index: i}
+
+		// the layout may contain commas, so it must be the last option.
+		if before, layout, ok := strings.Cut(options, "layout="); ok {
+			if sf.Type != timeType {
+				panic(fmt.Sprintf("queries: %s field with the `layout` option must be time.Time", sf.Name))
+			}
+			f.layout = layout
+			options = before
+		}
+
+		for _, option := range strings.Split(options, ",") {
+			switch option {
+			case "":
+			case "json":
+				f.json = true
+			case "omitzero":
+				f.omitZero = true
+			case "inout":
+				f.inOut = true
+			case "pk":
+				f.pk = true
+			case "version":
+				switch sf.Type.Kind() {
+				case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+					reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+				default:
+					panic(fmt.Sprintf("queries: %s field with the `version` option must be an integer", sf.Name))
+				}
+				f.version = true
+			default:
+				panic(fmt.Sprintf("queries: %s field has an unknown `%s` tag option %q", sf.Name, tagName, option))
+			}
 		}
 
-		fields[name] = field.Addr().Interface()
+		fields = append(fields, f)
 	}
 
 	return fields
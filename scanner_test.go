@@ -0,0 +1,88 @@
+package queries_test
+
+import (
+	"context"
+	"database/sql/driver"
+	"strings"
+	"testing"
+
+	"go-simpler.org/queries"
+	"go-simpler.org/queries/internal/assert"
+	. "go-simpler.org/queries/internal/assert/EF"
+)
+
+func TestCompile(t *testing.T) {
+	_, err := queries.Compile[user]([]string{"id", "email"})
+	assert.Equal[E](t, err.Error(), "queries: no field for the `email` column")
+}
+
+func TestCompile_ordinal(t *testing.T) {
+	type stats struct {
+		queries.Ordinal
+		Count int
+		Avg   float64
+	}
+
+	_, err := queries.Compile[stats]([]string{"count(*)", "avg(age)"})
+	assert.NoErr[E](t, err)
+
+	_, err = queries.Compile[stats]([]string{"count(*)"})
+	assert.Equal[E](t, err.Error(), "queries: queries_test.stats has 2 fields, but there are 1 columns")
+}
+
+func TestCompile_skipField(t *testing.T) {
+	type row struct {
+		ID      int    `sql:"id"`
+		Comment string `sql:"-"`
+	}
+
+	_, err := queries.Compile[row]([]string{"-"})
+	assert.Equal[E](t, err.Error(), "queries: no field for the `-` column")
+}
+
+func TestScanRows(t *testing.T) {
+	ctx := context.Background()
+
+	type row struct {
+		ID   int    `db:"id"`
+		Name string `db:"name"`
+	}
+
+	fdb := fakeDB{columns: []string{"ID", "NAME", "extra"}, rows: [][]driver.Value{{int64(1), "Alice", "x"}, {int64(2), "Bob", "y"}}}
+	rows, err := fdb.open(t).QueryContext(ctx, "select id, name, extra from users")
+	assert.NoErr[F](t, err)
+	defer rows.Close()
+
+	var got []row
+	for r, err := range queries.ScanRows[row](rows, queries.WithTag("db"), queries.MapColumns(strings.ToLower), queries.IgnoreUnknown()) {
+		assert.NoErr[F](t, err)
+		got = append(got, r)
+	}
+	assert.Equal[E](t, got, []row{{ID: 1, Name: "Alice"}, {ID: 2, Name: "Bob"}})
+}
+
+func TestScanAll(t *testing.T) {
+	ctx := context.Background()
+	fdb := fakeDB{columns: []string{"id", "name"}, rows: [][]driver.Value{{int64(1), "Alice"}, {int64(2), "Bob"}}}
+
+	rows, err := fdb.open(t).QueryContext(ctx, "select id, name from users")
+	assert.NoErr[F](t, err)
+	defer rows.Close()
+
+	var users []user
+	assert.NoErr[F](t, queries.ScanAll(&users, rows))
+	assert.Equal[E](t, users, []user{{ID: 1, Name: "Alice"}, {ID: 2, Name: "Bob"}})
+}
+
+func TestScanOne(t *testing.T) {
+	ctx := context.Background()
+	fdb := fakeDB{columns: []string{"id", "email"}, rows: [][]driver.Value{{int64(1), "alice@example.com"}}}
+
+	rows, err := fdb.open(t).QueryContext(ctx, "select id, email from users")
+	assert.NoErr[F](t, err)
+	defer rows.Close()
+
+	var u user
+	err = queries.ScanOne(&u, rows)
+	assert.Equal[E](t, err.Error(), "queries: no field for the `email` column")
+}
@@ -0,0 +1,56 @@
+package queries
+
+import (
+	"fmt"
+	"hash/fnv"
+)
+
+// ShardSet routes queries to one of the shards by a key, e.g. a tenant id:
+//
+//	user, err := queries.QueryOne[User](ctx, shards.ForKey(tenant), "select * from users where id = $1", id)
+//
+// During resharding, set Previous to keep the old routing and read from both shards with [ShardSet.ForKeyAll] and [FanOut].
+type ShardSet struct {
+	Shards []QueryExecer
+
+	// Route returns the index of the shard for the key.
+	// If nil, the FNV-1a hash of the key modulo the number of shards is used.
+	Route func(key string) int
+
+	// Previous, if not nil, returns the index of the shard the key was routed to before resharding.
+	Previous func(key string) int
+}
+
+// ForKey returns the shard for the key.
+func (s *ShardSet) ForKey(key string) QueryExecer {
+	return s.Shards[s.index(s.Route, key)]
+}
+
+// ForKeyAll returns the shard for the key followed by the shard the key was routed to before resharding,
+// if Previous is set and the shards differ.
+func (s *ShardSet) ForKeyAll(key string) []Queryer {
+	i := s.index(s.Route, key)
+	shards := []Queryer{s.Shards[i]}
+	if s.Previous != nil {
+		if j := s.index(s.Previous, key); j != i {
+			shards = append(shards, s.Shards[j])
+		}
+	}
+	return shards
+}
+
+func (s *ShardSet) index(route func(string) int, key string) int {
+	if len(s.Shards) == 0 {
+		panic("queries: no shards")
+	}
+	if route == nil {
+		h := fnv.New32a()
+		_, _ = h.Write([]byte(key))
+		return int(h.Sum32() % uint32(len(s.Shards)))
+	}
+	i := route(key)
+	if i < 0 || i >= len(s.Shards) {
+		panic(fmt.Sprintf("queries: shard index %d out of range", i))
+	}
+	return i
+}
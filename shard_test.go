@@ -0,0 +1,29 @@
+package queries_test
+
+import (
+	"testing"
+
+	"go-simpler.org/queries"
+	"go-simpler.org/queries/internal/assert"
+	. "go-simpler.org/queries/internal/assert/EF"
+)
+
+func TestShardSet(t *testing.T) {
+	var fdb1, fdb2, fdb3 fakeDB
+	db1, db2, db3 := fdb1.open(t), fdb2.open(t), fdb3.open(t)
+
+	shards := queries.ShardSet{
+		Shards:   []queries.QueryExecer{db1, db2, db3},
+		Route:    func(key string) int { return len(key) % 3 },
+		Previous: func(key string) int { return len(key) % 2 },
+	}
+	assert.Equal[E](t, shards.ForKey("a"), queries.QueryExecer(db2))
+	assert.Equal[E](t, shards.ForKeyAll("a"), []queries.Queryer{db2})
+	assert.Equal[E](t, shards.ForKeyAll("ab"), []queries.Queryer{db3, db1})
+
+	shards.Route = nil
+	assert.Equal[E](t, shards.ForKey("a"), shards.ForKey("a"))
+
+	shards.Route = func(string) int { return 3 }
+	assert.Panics[E](t, func() { shards.ForKey("a") }, "queries: shard index 3 out of range")
+}
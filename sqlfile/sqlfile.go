@@ -0,0 +1,113 @@
+// Package sqlfile loads named queries from .sql files (e.g. embedded with [embed.FS]).
+//
+// Each query starts with a "-- name:" marker and lasts until the next one:
+//
+//	-- name: get-user
+//	select * from users where id = %P;
+//
+//	-- name: list-users
+//	select * from users order by %I limit %P;
+//
+// The queries are format strings for [queries.Builder.Appendf],
+// so with the %P and %I verbs one file serves every dialect (a literal % must be written as %%).
+package sqlfile
+
+import (
+	"bufio"
+	"fmt"
+	"io/fs"
+	"strings"
+
+	"go-simpler.org/queries"
+)
+
+// Queries is a set of named queries.
+type Queries struct {
+	queries map[string]string
+}
+
+// Load parses the files matching the patterns (see [fs.Glob]).
+// The names of the queries must be unique across the files.
+func Load(fsys fs.FS, patterns ...string) (*Queries, error) {
+	q := &Queries{queries: make(map[string]string)}
+	for _, pattern := range patterns {
+		names, err := fs.Glob(fsys, pattern)
+		if err != nil {
+			return nil, fmt.Errorf("matching %q: %w", pattern, err)
+		}
+		for _, name := range names {
+			data, err := fs.ReadFile(fsys, name)
+			if err != nil {
+				return nil, fmt.Errorf("reading %s: %w", name, err)
+			}
+			if err := q.parse(name, string(data)); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return q, nil
+}
+
+const marker = "-- name:"
+
+func (q *Queries) parse(file, data string) error {
+	var name string
+	var body strings.Builder
+
+	add := func() error {
+		if name == "" {
+			return nil
+		}
+		if _, ok := q.queries[name]; ok {
+			return fmt.Errorf("sqlfile: %s: duplicate query %q", file, name)
+		}
+		query := strings.TrimSpace(body.String())
+		query = strings.TrimSpace(strings.TrimSuffix(query, ";"))
+		if query == "" {
+			return fmt.Errorf("sqlfile: %s: empty query %q", file, name)
+		}
+		q.queries[name] = query
+		body.Reset()
+		return nil
+	}
+
+	sc := bufio.NewScanner(strings.NewReader(data))
+	for line := 1; sc.Scan(); line++ {
+		text := sc.Text()
+		if rest, ok := strings.CutPrefix(strings.TrimSpace(text), marker); ok {
+			if err := add(); err != nil {
+				return err
+			}
+			name = strings.TrimSpace(rest)
+			if name == "" {
+				return fmt.Errorf("sqlfile: %s:%d: empty query name", file, line)
+			}
+			continue
+		}
+		if name == "" {
+			continue // e.g. a header comment.
+		}
+		body.WriteString(text)
+		body.WriteByte('\n')
+	}
+	if err := sc.Err(); err != nil {
+		return fmt.Errorf("reading %s: %w", file, err)
+	}
+	return add()
+}
+
+// Get returns the query with the given name.
+func (q *Queries) Get(name string) (string, bool) {
+	query, ok := q.queries[name]
+	return query, ok
+}
+
+// Appendf appends the query with the given name to the builder, formatted with the arguments (see [queries.Builder.Appendf]).
+// It panics if there is no such query.
+func (q *Queries) Appendf(qb *queries.Builder, name string, args ...any) {
+	query, ok := q.queries[name]
+	if !ok {
+		panic(fmt.Sprintf("sqlfile: no query %q", name))
+	}
+	qb.Appendf(query, args...)
+}
@@ -0,0 +1,51 @@
+package sqlfile_test
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"go-simpler.org/queries"
+	"go-simpler.org/queries/internal/assert"
+	. "go-simpler.org/queries/internal/assert/EF"
+	"go-simpler.org/queries/sqlfile"
+)
+
+func TestLoad(t *testing.T) {
+	fsys := fstest.MapFS{
+		"sql/users.sql": {Data: []byte(`-- queries for the users table.
+
+-- name: get-user
+select * from users
+where id = %P;
+
+-- name: find-users
+select * from users where name like %P || '%%' order by %I
+`)},
+		"sql/orders.sql": {Data: []byte("-- name: get-order\nselect * from orders where id = %P\n")},
+	}
+
+	q, err := sqlfile.Load(fsys, "sql/*.sql")
+	assert.NoErr[F](t, err)
+
+	query, ok := q.Get("get-user")
+	assert.Equal[E](t, ok, true)
+	assert.Equal[E](t, query, "select * from users\nwhere id = %P")
+
+	for dialect, want := range map[queries.Dialect]string{
+		queries.PostgreSQL: `select * from users where name like $1 || '%' order by "name"`,
+		queries.MySQL:      "select * from users where name like ? || '%' order by `name`",
+	} {
+		qb := queries.Builder{Dialect: dialect}
+		q.Appendf(&qb, "find-users", "Al", "name")
+		assert.Equal[E](t, qb.String(), want)
+	}
+
+	t.Run("duplicate", func(t *testing.T) {
+		fsys := fstest.MapFS{
+			"a.sql": {Data: []byte("-- name: q\nselect 1\n")},
+			"b.sql": {Data: []byte("-- name: q\nselect 2\n")},
+		}
+		_, err := sqlfile.Load(fsys, "*.sql")
+		assert.Equal[E](t, err.Error(), `sqlfile: b.sql: duplicate query "q"`)
+	})
+}
@@ -0,0 +1,55 @@
+package queries
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"iter"
+)
+
+// Preparer is an interface implemented by [sql.DB], [sql.Tx] and [sql.Conn].
+type Preparer interface {
+	PrepareContext(ctx context.Context, query string) (*sql.Stmt, error)
+}
+
+// Stmt is a prepared statement whose rows are scanned into T.
+// Use [Prepare] to create one.
+type Stmt[T any] struct {
+	stmt  *sql.Stmt
+	query string
+}
+
+// Prepare creates a prepared statement for later queries.
+// The statement must be closed when no longer needed.
+func Prepare[T any](ctx context.Context, p Preparer, query string) (*Stmt[T], error) {
+	stmt, err := p.PrepareContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("preparing query %q: %w", query, err)
+	}
+	return &Stmt[T]{stmt: stmt, query: query}, nil
+}
+
+// Query executes the statement with the arguments, see [Query].
+func (s *Stmt[T]) Query(ctx context.Context, args ...any) iter.Seq2[T, error] {
+	return Query[T](ctx, stmtQueryer{s.stmt}, s.query, args...)
+}
+
+// QueryOne executes the statement with the arguments, see [QueryOne].
+func (s *Stmt[T]) QueryOne(ctx context.Context, args ...any) (T, error) {
+	return QueryOne[T](ctx, stmtQueryer{s.stmt}, s.query, args...)
+}
+
+// QueryMaybe executes the statement with the arguments, see [QueryMaybe].
+func (s *Stmt[T]) QueryMaybe(ctx context.Context, args ...any) (T, bool, error) {
+	return QueryMaybe[T](ctx, stmtQueryer{s.stmt}, s.query, args...)
+}
+
+// Close closes the statement.
+func (s *Stmt[T]) Close() error { return s.stmt.Close() }
+
+// stmtQueryer adapts a prepared statement to the [Queryer] interface, the query is ignored.
+type stmtQueryer struct{ stmt *sql.Stmt }
+
+func (q stmtQueryer) QueryContext(ctx context.Context, _ string, args ...any) (*sql.Rows, error) {
+	return q.stmt.QueryContext(ctx, args...)
+}
@@ -0,0 +1,30 @@
+package queries_test
+
+import (
+	"context"
+	"database/sql/driver"
+	"testing"
+
+	"go-simpler.org/queries"
+	"go-simpler.org/queries/internal/assert"
+	. "go-simpler.org/queries/internal/assert/EF"
+)
+
+func TestPrepare(t *testing.T) {
+	ctx := context.Background()
+	fdb := fakeDB{columns: []string{"id", "name"}, rows: [][]driver.Value{{int64(1), "Alice"}}}
+
+	conn, err := fdb.open(t).Conn(ctx)
+	assert.NoErr[F](t, err)
+	defer conn.Close()
+
+	stmt, err := queries.Prepare[user](ctx, conn, "select id, name from users where id = ?")
+	assert.NoErr[F](t, err)
+	defer stmt.Close()
+
+	u, err := stmt.QueryOne(ctx, 1)
+	assert.NoErr[F](t, err)
+	assert.Equal[E](t, u, user{ID: 1, Name: "Alice"})
+	assert.Equal[E](t, fdb.queries, []string{"select id, name from users where id = ?"})
+	assert.Equal[E](t, fdb.args, [][]any{{int64(1)}})
+}
@@ -0,0 +1,204 @@
+package queries
+
+import (
+	"container/list"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// StmtCache wraps a [Preparer] (usually [sql.DB]) and executes the queries as prepared statements,
+// which are cached by the query text, so that the drivers that prepare each query anyway (e.g. for MSSQL and Oracle)
+// do it once per query.
+// The least recently used statements are closed once the cache is full.
+// It can be passed to the package helpers, such as [Query] and [Exec], in place of the wrapped value.
+// It is safe for concurrent use.
+type StmtCache struct {
+	p    Preparer
+	size int
+
+	mu     sync.Mutex
+	lru    *list.List               // of *cachedStmt, the most recently used first.
+	stmts  map[string]*list.Element // by query.
+	closed bool
+}
+
+type cachedStmt struct {
+	query   string
+	stmt    *sql.Stmt
+	users   int  // the number of queries currently using the statement.
+	evicted bool // the statement is no longer cached and is closed once unused.
+}
+
+// NewStmtCache returns a [StmtCache] that keeps up to size statements.
+func NewStmtCache(p Preparer, size int) *StmtCache {
+	if size <= 0 {
+		panic("queries: size must be positive")
+	}
+	return &StmtCache{p: p, size: size, lru: list.New(), stmts: make(map[string]*list.Element)}
+}
+
+// QueryContext implements the [Queryer] interface.
+func (c *StmtCache) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	cs, err := c.acquire(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	// once the rows are returned, the statement can be closed: it is only closed after the rows are.
+	defer c.release(cs)
+	return cs.stmt.QueryContext(ctx, args...)
+}
+
+// ExecContext implements the [Execer] interface.
+func (c *StmtCache) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	cs, err := c.acquire(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer c.release(cs)
+	return cs.stmt.ExecContext(ctx, args...)
+}
+
+// Tx returns a view of the cache that executes the queries within the transaction,
+// e.g. in the function passed to [InTx]. The cached statements are reused via [sql.Tx.StmtContext]
+// (and the missing ones are prepared and cached as usual), so they are not prepared again for each transaction.
+// The view must not be used after the transaction ends.
+func (c *StmtCache) Tx(tx *sql.Tx) *TxStmtCache {
+	return &TxStmtCache{c: c, tx: tx, stmts: make(map[string]*sql.Stmt)}
+}
+
+// TxStmtCache is a view of a [StmtCache] bound to a transaction, see [StmtCache.Tx].
+// It can be passed to the package helpers in place of the transaction.
+// It is safe for concurrent use.
+type TxStmtCache struct {
+	c  *StmtCache
+	tx *sql.Tx
+
+	mu    sync.Mutex
+	stmts map[string]*sql.Stmt // by query; closed by the transaction when it ends.
+}
+
+// QueryContext implements the [Queryer] interface.
+func (t *TxStmtCache) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	stmt, err := t.stmt(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return stmt.QueryContext(ctx, args...)
+}
+
+// ExecContext implements the [Execer] interface.
+func (t *TxStmtCache) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	stmt, err := t.stmt(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return stmt.ExecContext(ctx, args...)
+}
+
+// stmt returns the transaction-specific statement for the query, made from the cached one.
+func (t *TxStmtCache) stmt(ctx context.Context, query string) (*sql.Stmt, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if stmt, ok := t.stmts[query]; ok {
+		return stmt, nil
+	}
+
+	cs, err := t.c.acquire(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	// the transaction-specific statement does not depend on the cached one once created.
+	defer t.c.release(cs)
+
+	stmt := t.tx.StmtContext(ctx, cs.stmt)
+	t.stmts[query] = stmt
+	return stmt, nil
+}
+
+// acquire returns the cached statement for the query, preparing it if needed.
+// The statement is not closed until it is released, even if it is evicted meanwhile.
+func (c *StmtCache) acquire(ctx context.Context, query string) (*cachedStmt, error) {
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		return nil, errors.New("queries: statement cache is closed")
+	}
+	if e, ok := c.stmts[query]; ok {
+		c.lru.MoveToFront(e)
+		cs := e.Value.(*cachedStmt)
+		cs.users++
+		c.mu.Unlock()
+		return cs, nil
+	}
+	c.mu.Unlock()
+
+	// the statement is prepared without holding the lock, so other queries are not blocked.
+	stmt, err := c.p.PrepareContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("preparing query %q: %w", query, err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		_ = stmt.Close()
+		return nil, errors.New("queries: statement cache is closed")
+	}
+	if e, ok := c.stmts[query]; ok { // prepared concurrently.
+		_ = stmt.Close()
+		c.lru.MoveToFront(e)
+		cs := e.Value.(*cachedStmt)
+		cs.users++
+		return cs, nil
+	}
+
+	cs := &cachedStmt{query: query, stmt: stmt, users: 1}
+	c.stmts[query] = c.lru.PushFront(cs)
+	if c.lru.Len() > c.size {
+		oldest := c.lru.Remove(c.lru.Back()).(*cachedStmt)
+		delete(c.stmts, oldest.query)
+		_ = c.evict(oldest)
+	}
+	return cs, nil
+}
+
+// release marks the statement as no longer used by the caller of acquire, closing it if it has been evicted.
+func (c *StmtCache) release(cs *cachedStmt) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	cs.users--
+	if cs.evicted && cs.users == 0 {
+		_ = cs.stmt.Close()
+	}
+}
+
+// evict closes the statement or, if it is in use, defers closing it to the last release.
+// The caller must hold the lock.
+func (c *StmtCache) evict(cs *cachedStmt) error {
+	cs.evicted = true
+	if cs.users > 0 {
+		return nil
+	}
+	return cs.stmt.Close()
+}
+
+// Close closes the cached statements; the ones in use are closed once their queries return.
+// The wrapped value is not closed.
+func (c *StmtCache) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.closed = true
+
+	var errs []error
+	for e := c.lru.Front(); e != nil; e = e.Next() {
+		if err := c.evict(e.Value.(*cachedStmt)); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	c.lru.Init()
+	clear(c.stmts)
+	return errors.Join(errs...)
+}
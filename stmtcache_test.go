@@ -0,0 +1,98 @@
+package queries_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"go-simpler.org/queries"
+	"go-simpler.org/queries/internal/assert"
+	. "go-simpler.org/queries/internal/assert/EF"
+)
+
+func TestStmtCache(t *testing.T) {
+	ctx := context.Background()
+	fdb := fakeDB{columns: []string{"id", "name"}, rows: [][]driver.Value{{int64(1), "Alice"}}}
+	p := &countingPreparer{DB: fdb.open(t)}
+
+	cache := queries.NewStmtCache(p, 1)
+
+	for _, query := range []string{"select 1", "select 1", "select 2", "select 1"} {
+		_, err := queries.QueryOne[user](ctx, cache, query)
+		assert.NoErr[F](t, err)
+	}
+	assert.Equal[E](t, p.prepared, []string{"select 1", "select 2", "select 1"})
+	assert.Equal[E](t, fdb.queries, []string{"select 1", "select 1", "select 2", "select 1"})
+
+	assert.NoErr[F](t, cache.Close())
+	_, err := queries.Exec(ctx, cache, "delete from users")
+	assert.Equal[E](t, err.Error(), `executing query "delete from users": queries: statement cache is closed`)
+}
+
+func TestStmtCache_concurrent(t *testing.T) {
+	ctx := context.Background()
+	fdb := fakeDB{columns: []string{"id", "name"}, rows: [][]driver.Value{{int64(1), "Alice"}}}
+	cache := queries.NewStmtCache(fdb.open(t), 1)
+	defer cache.Close()
+
+	var wg sync.WaitGroup
+	var errs atomic.Int64
+	for i := range 32 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range 50 {
+				query := fmt.Sprintf("select %d", (i+j)%7)
+				if _, err := queries.QueryOne[user](ctx, cache, query); err != nil {
+					errs.Add(1)
+				}
+				if _, err := queries.Exec(ctx, cache, query); err != nil {
+					errs.Add(1)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal[E](t, errs.Load(), int64(0))
+}
+
+func TestStmtCache_Tx(t *testing.T) {
+	ctx := context.Background()
+	fdb := fakeDB{columns: []string{"id", "name"}, rows: [][]driver.Value{{int64(1), "Alice"}}, result: fakeResult{rowsAffected: 1}}
+	db := fdb.open(t)
+	p := &countingPreparer{DB: db}
+
+	cache := queries.NewStmtCache(p, 2)
+	defer cache.Close()
+
+	_, err := queries.QueryOne[user](ctx, cache, "select 1")
+	assert.NoErr[F](t, err)
+
+	for range 2 {
+		err = queries.InTx(ctx, db, nil, queries.RetryPolicy{}, func(tx *sql.Tx) error {
+			tc := cache.Tx(tx)
+			if _, err := queries.QueryOne[user](ctx, tc, "select 1"); err != nil {
+				return err
+			}
+			_, err := queries.Exec(ctx, tc, "delete from users")
+			return err
+		})
+		assert.NoErr[F](t, err)
+	}
+	assert.Equal[E](t, p.prepared, []string{"select 1", "delete from users"})
+	assert.Equal[E](t, fdb.queries, []string{"select 1", "select 1", "delete from users", "select 1", "delete from users"})
+}
+
+type countingPreparer struct {
+	*sql.DB
+	prepared []string
+}
+
+func (p *countingPreparer) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	p.prepared = append(p.prepared, query)
+	return p.DB.PrepareContext(ctx, query)
+}
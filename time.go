@@ -0,0 +1,46 @@
+package queries
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"time"
+)
+
+// Time wraps a [time.Time] stored as text in the given layout (see [time.Parse]).
+// It implements [sql.Scanner] and [driver.Valuer].
+// Struct fields with the `layout` tag option (e.g. `sql:"created_at,layout=2006-01-02"`) are wrapped automatically when scanning.
+type Time struct {
+	T      *time.Time
+	Layout string
+}
+
+// Scan implements the [sql.Scanner] interface.
+// NULL leaves T unchanged.
+func (t Time) Scan(src any) error {
+	var s string
+	switch src := src.(type) {
+	case nil:
+		return nil
+	case time.Time: // the driver has already parsed it.
+		*t.T = src
+		return nil
+	case []byte:
+		s = string(src)
+	case string:
+		s = src
+	default:
+		return fmt.Errorf("queries: cannot parse %T as time", src)
+	}
+
+	parsed, err := time.Parse(t.Layout, s)
+	if err != nil {
+		return err
+	}
+	*t.T = parsed
+	return nil
+}
+
+// Value implements the [driver.Valuer] interface.
+func (t Time) Value() (driver.Value, error) {
+	return t.T.Format(t.Layout), nil
+}
@@ -0,0 +1,142 @@
+package queries
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// TxBeginner is an interface implemented by [sql.DB] and [sql.Conn].
+type TxBeginner interface {
+	BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error)
+}
+
+// RetryPolicy controls how [InTx] retries a transaction.
+type RetryPolicy struct {
+	MaxAttempts int              // the maximum number of attempts, including the first one; 1 if zero.
+	Backoff     time.Duration    // the delay before the first retry, doubled for each next one.
+	Retryable   func(error) bool // reports whether the transaction can be retried after the error; [IsRetryable] if nil.
+}
+
+// InTx runs fn in a transaction, which is committed if fn returns nil and rolled back otherwise.
+// If fn or the commit fails with a retryable error (e.g. a serialization failure or a deadlock),
+// the whole transaction is retried according to the policy, so fn must be safe to call several times.
+func InTx(ctx context.Context, db TxBeginner, opts *sql.TxOptions, policy RetryPolicy, fn func(tx *sql.Tx) error) error {
+	retryable := policy.Retryable
+	if retryable == nil {
+		retryable = IsRetryable
+	}
+	backoff := policy.Backoff
+
+	for attempt := 1; ; attempt++ {
+		err := runTx(ctx, db, opts, fn)
+		if err == nil || attempt >= policy.MaxAttempts || !retryable(err) {
+			return err
+		}
+
+		if backoff > 0 {
+			timer := time.NewTimer(backoff)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return errors.Join(ctx.Err(), err)
+			case <-timer.C:
+			}
+			backoff *= 2
+		}
+	}
+}
+
+func runTx(ctx context.Context, db TxBeginner, opts *sql.TxOptions, fn func(tx *sql.Tx) error) error {
+	tx, err := db.BeginTx(ctx, opts)
+	if err != nil {
+		return fmt.Errorf("beginning transaction: %w", err)
+	}
+	// also on panic; after a successful commit, it is a no-op.
+	defer func() { _ = tx.Rollback() }()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("committing transaction: %w", err)
+	}
+	return nil
+}
+
+// IsRetryable reports whether the transaction can be retried after the error, i.e. the error is one of:
+//
+//   - a serialization failure (SQLSTATE 40001) or a deadlock (SQLSTATE 40P01),
+//     reported by the drivers whose errors have the SQLState() string method (e.g. pgx, lib/pq);
+//   - a deadlock (1213) or a lock wait timeout (1205) reported by the github.com/go-sql-driver/mysql driver;
+//   - SQLITE_BUSY (5) or SQLITE_LOCKED (6), including their extended codes,
+//     reported by the modernc.org/sqlite or github.com/mattn/go-sqlite3 drivers.
+//
+// For SQLite, also consider beginning the transactions in the immediate mode (see [SQLiteImmediate]).
+func IsRetryable(err error) bool {
+	var state interface{ SQLState() string }
+	if errors.As(err, &state) {
+		switch state.SQLState() {
+		case "40001", "40P01":
+			return true
+		}
+	}
+
+	// the package does not depend on the MySQL and SQLite drivers, so their errors are inspected by reflection.
+	for e := err; e != nil; e = errors.Unwrap(e) {
+		v := reflect.Indirect(reflect.ValueOf(e))
+		if v.Kind() != reflect.Struct {
+			continue
+		}
+		if v.Type().Name() == "MySQLError" {
+			if n := v.FieldByName("Number"); n.IsValid() && n.CanUint() {
+				switch n.Uint() {
+				case 1213, 1205:
+					return true
+				}
+			}
+			continue
+		}
+		if code, ok := sqliteCode(e, v); ok {
+			switch code & 0xff { // the primary result code of an extended one.
+			case 5, 6: // SQLITE_BUSY, SQLITE_LOCKED
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// sqliteCode returns the result code of the SQLite driver error:
+// the Error type of modernc.org/sqlite has the Code() int method,
+// the one of github.com/mattn/go-sqlite3 has the Code, ExtendedCode and SystemErrno fields.
+func sqliteCode(err error, v reflect.Value) (int64, bool) {
+	if e, ok := err.(interface{ Code() int }); ok && v.Type().Name() == "Error" {
+		return int64(e.Code()), true
+	}
+	code := v.FieldByName("Code")
+	if code.IsValid() && code.CanInt() && v.FieldByName("ExtendedCode").IsValid() && v.FieldByName("SystemErrno").IsValid() {
+		return code.Int(), true
+	}
+	return 0, false
+}
+
+// SQLiteImmediate adds the _txlock=immediate parameter to the SQLite data source name,
+// which is supported by both modernc.org/sqlite and github.com/mattn/go-sqlite3.
+// The transactions then begin with BEGIN IMMEDIATE and take the write lock upfront,
+// so a concurrent writer fails at the beginning with SQLITE_BUSY (retried by [InTx]),
+// instead of in the middle of a transaction that cannot be upgraded to a write one.
+func SQLiteImmediate(dsn string) string {
+	name, query, _ := strings.Cut(dsn, "?")
+	params, err := url.ParseQuery(query)
+	if err != nil {
+		panic(fmt.Sprintf("queries: bad data source name %q: %v", dsn, err))
+	}
+	params.Set("_txlock", "immediate")
+	return name + "?" + params.Encode()
+}
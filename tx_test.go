@@ -0,0 +1,90 @@
+package queries_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"testing"
+
+	"go-simpler.org/queries"
+	"go-simpler.org/queries/internal/assert"
+	. "go-simpler.org/queries/internal/assert/EF"
+)
+
+func TestInTx(t *testing.T) {
+	ctx := context.Background()
+	errSerialization := sqlStateError("40001")
+	errConstraint := sqlStateError("23505")
+
+	tests := map[string]struct {
+		errs     []error // the errors returned by fn one by one.
+		attempts int
+		err      error
+	}{
+		"ok":            {errs: nil, attempts: 1},
+		"retried":       {errs: []error{errSerialization, errSerialization}, attempts: 3},
+		"not retryable": {errs: []error{errConstraint}, attempts: 1, err: errConstraint},
+		"max attempts":  {errs: []error{errSerialization, errSerialization, errSerialization}, attempts: 3, err: errSerialization},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var fdb fakeDB
+			var attempts int
+			err := queries.InTx(ctx, fdb.open(t), nil, queries.RetryPolicy{MaxAttempts: 3}, func(*sql.Tx) error {
+				attempts++
+				if attempts <= len(tt.errs) {
+					return tt.errs[attempts-1]
+				}
+				return nil
+			})
+			assert.Equal[E](t, attempts, tt.attempts)
+			assert.IsErr[E](t, err, tt.err)
+		})
+	}
+}
+
+func TestIsRetryable(t *testing.T) {
+	assert.Equal[E](t, queries.IsRetryable(fmt.Errorf("wrapped: %w", sqlStateError("40001"))), true)
+	assert.Equal[E](t, queries.IsRetryable(sqlStateError("23505")), false)
+	assert.Equal[E](t, queries.IsRetryable(&MySQLError{Number: 1213}), true)
+	assert.Equal[E](t, queries.IsRetryable(&MySQLError{Number: 1062}), false)
+	assert.Equal[E](t, queries.IsRetryable(errors.New("foo")), false)
+
+	assert.Equal[E](t, queries.IsRetryable(fmt.Errorf("wrapped: %w", &Error{code: 5})), true)
+	assert.Equal[E](t, queries.IsRetryable(&Error{code: 517}), true) // SQLITE_BUSY_SNAPSHOT
+	assert.Equal[E](t, queries.IsRetryable(&Error{code: 19}), false)
+	assert.Equal[E](t, queries.IsRetryable(sqlite3Error{Code: 6, ExtendedCode: 262}), true) // SQLITE_LOCKED_SHAREDCACHE
+	assert.Equal[E](t, queries.IsRetryable(sqlite3Error{Code: 19, ExtendedCode: 2067}), false)
+}
+
+func TestSQLiteImmediate(t *testing.T) {
+	assert.Equal[E](t, queries.SQLiteImmediate("app.db"), "app.db?_txlock=immediate")
+	assert.Equal[E](t, queries.SQLiteImmediate("file:app.db?cache=shared&_txlock=deferred"), "file:app.db?_txlock=immediate&cache=shared")
+}
+
+type sqlStateError string
+
+func (e sqlStateError) Error() string    { return "sqlstate " + string(e) }
+func (e sqlStateError) SQLState() string { return string(e) }
+
+// MySQLError mimics the error of the github.com/go-sql-driver/mysql driver.
+type MySQLError struct{ Number uint16 }
+
+func (e *MySQLError) Error() string { return fmt.Sprintf("Error %d", e.Number) }
+
+// Error mimics the error of the modernc.org/sqlite driver, including its type name.
+type Error struct{ code int }
+
+func (e *Error) Error() string { return fmt.Sprintf("sqlite error %d", e.code) }
+func (e *Error) Code() int     { return e.code }
+
+// sqlite3Error mimics the error of the github.com/mattn/go-sqlite3 driver.
+type sqlite3Error struct {
+	Code         int
+	ExtendedCode int
+	SystemErrno  int
+}
+
+func (e sqlite3Error) Error() string { return fmt.Sprintf("sqlite3 error %d", e.ExtendedCode) }
@@ -0,0 +1,77 @@
+package queries
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+)
+
+// AppendSet appends the SET clause of the UPDATE statement (e.g. " set name = $1, age = $2") to the query.
+// The row must be either a struct (or a pointer to a struct) or a map[string]any.
+// For a struct, its fields tagged with `sql` are used as the columns and the values;
+// fields with the `omitzero` tag option are skipped if they have the zero value, which is useful for PATCH-style updates.
+// For a map, the keys are used as the columns in sorted order.
+func (b *Builder) AppendSet(row any) {
+	var columns []string
+	var values []any
+
+	if m, ok := row.(map[string]any); ok {
+		for column := range m {
+			columns = append(columns, column)
+		}
+		slices.Sort(columns)
+		for _, column := range columns {
+			values = append(values, m[column])
+		}
+	} else {
+		v := reflect.Indirect(reflect.ValueOf(row))
+		if v.Kind() != reflect.Struct {
+			panic("queries: row must be a struct, a pointer to a struct or a map[string]any")
+		}
+		for _, f := range parseStruct(v.Type()) {
+			if f.omitZero && v.Field(f.index).IsZero() {
+				continue
+			}
+			columns = append(columns, f.column)
+			values = append(values, f.value(v))
+		}
+	}
+
+	if len(columns) == 0 {
+		panic("queries: row has no columns to update")
+	}
+
+	verb := b.verb()
+	b.query.WriteString(" set ")
+	for i, column := range columns {
+		if i > 0 {
+			b.query.WriteString(", ")
+		}
+		fmt.Fprintf(&b.query, "%s = %s", column, b.bind(verb, values[i]))
+	}
+}
+
+// UpdateDiff compares the fields of two struct values tagged with `sql` and returns the new values of the changed columns.
+// The result can be passed to [Builder.AppendSet] to update only these columns;
+// if it is empty, there is nothing to update:
+//
+//	set := queries.UpdateDiff(before, user)
+//	if len(set) > 0 {
+//		qb.Appendf("update users")
+//		qb.AppendSet(set)
+//		qb.Appendf(" where id = %P", user.ID)
+//	}
+func UpdateDiff[T any](before, after T) map[string]any {
+	vo, vn := reflect.Indirect(reflect.ValueOf(before)), reflect.Indirect(reflect.ValueOf(after))
+	if vo.Kind() != reflect.Struct {
+		panic("queries: T must be a struct or a pointer to a struct")
+	}
+
+	set := make(map[string]any)
+	for _, f := range parseStruct(vo.Type()) {
+		if !equalValues(vo.Field(f.index), vn.Field(f.index)) {
+			set[f.column] = f.value(vn)
+		}
+	}
+	return set
+}
@@ -0,0 +1,66 @@
+package queries_test
+
+import (
+	"testing"
+
+	"go-simpler.org/queries"
+	"go-simpler.org/queries/internal/assert"
+	. "go-simpler.org/queries/internal/assert/EF"
+)
+
+func TestBuilder_AppendSet(t *testing.T) {
+	type patch struct {
+		Name string `sql:"name,omitzero"`
+		Age  int    `sql:"age,omitzero"`
+	}
+
+	tests := map[string]struct {
+		row   any
+		query string
+		args  []any
+	}{
+		"struct": {
+			row:   patch{Age: 30},
+			query: "update users set age = $1 where id = $2",
+			args:  []any{30, 1},
+		},
+		"map": {
+			row:   map[string]any{"name": "Alice", "age": 30},
+			query: "update users set age = $1, name = $2 where id = $3",
+			args:  []any{30, "Alice", 1},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			qb := queries.Builder{Dialect: queries.PostgreSQL}
+			qb.Appendf("update users")
+			qb.AppendSet(tt.row)
+			qb.Appendf(" where id = %$", 1)
+			assert.Equal[E](t, qb.String(), tt.query)
+			assert.Equal[E](t, qb.Args, tt.args)
+		})
+	}
+}
+
+func TestUpdateDiff(t *testing.T) {
+	type row struct {
+		ID   int            `sql:"id"`
+		Name string         `sql:"name"`
+		Age  int            `sql:"age"`
+		Tags map[string]int `sql:"tags,json"`
+	}
+
+	before := row{ID: 1, Name: "Alice", Age: 30, Tags: map[string]int{"a": 1}}
+	after := row{ID: 1, Name: "Alicia", Age: 30, Tags: map[string]int{"a": 2}}
+
+	set := queries.UpdateDiff(before, after)
+	assert.Equal[E](t, set, map[string]any{"name": "Alicia", "tags": queries.JSON{V: map[string]int{"a": 2}}})
+	assert.Equal[E](t, len(queries.UpdateDiff(&before, &before)), 0)
+
+	qb := queries.Builder{Dialect: queries.PostgreSQL}
+	qb.Appendf("update users")
+	qb.AppendSet(set)
+	qb.Appendf(" where id = %$", after.ID)
+	assert.Equal[E](t, qb.String(), "update users set name = $1, tags = $2 where id = $3")
+}
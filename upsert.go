@@ -0,0 +1,55 @@
+package queries
+
+import (
+	"fmt"
+	"strings"
+)
+
+// AppendUpsert appends the clause that turns the preceding INSERT statement into an upsert:
+// if a row with the same conflict columns already exists, its update columns are set to the new values instead.
+// If no update columns are specified, the conflicting row is left unchanged;
+// for PostgreSQL and SQLite, the conflict columns can then be omitted to ignore any conflict.
+// It renders ON CONFLICT for PostgreSQL and SQLite and ON DUPLICATE KEY UPDATE for MySQL,
+// which ignores the conflict columns and checks all unique indexes instead.
+// It panics for MSSQL and Oracle, which only support MERGE.
+func (b *Builder) AppendUpsert(conflict, update []string) {
+	d := b.dialect()
+	if !d.Info().Upsert {
+		panic(fmt.Sprintf("queries: %s does not support upserts", d))
+	}
+
+	if d == MySQL {
+		if len(update) == 0 {
+			if len(conflict) == 0 {
+				panic("queries: either conflict or update columns must be specified")
+			}
+			// MySQL has no DO NOTHING, a no-op update is used instead.
+			fmt.Fprintf(&b.query, " on duplicate key update %s = %s", conflict[0], conflict[0])
+			return
+		}
+		set := make([]string, len(update))
+		for i, column := range update {
+			set[i] = fmt.Sprintf("%s = values(%s)", column, column)
+		}
+		fmt.Fprintf(&b.query, " on duplicate key update %s", strings.Join(set, ", "))
+		return
+	}
+
+	if len(conflict) == 0 {
+		if len(update) > 0 {
+			panic("queries: conflict columns must be specified to update the conflicting row")
+		}
+		b.query.WriteString(" on conflict do nothing") // any conflict.
+		return
+	}
+	fmt.Fprintf(&b.query, " on conflict (%s)", strings.Join(conflict, ", "))
+	if len(update) == 0 {
+		b.query.WriteString(" do nothing")
+		return
+	}
+	set := make([]string, len(update))
+	for i, column := range update {
+		set[i] = fmt.Sprintf("%s = excluded.%s", column, column)
+	}
+	fmt.Fprintf(&b.query, " do update set %s", strings.Join(set, ", "))
+}
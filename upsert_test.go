@@ -0,0 +1,62 @@
+package queries_test
+
+import (
+	"testing"
+
+	"go-simpler.org/queries"
+	"go-simpler.org/queries/internal/assert"
+	. "go-simpler.org/queries/internal/assert/EF"
+)
+
+func TestBuilder_AppendUpsert(t *testing.T) {
+	tests := map[string]struct {
+		dialect  queries.Dialect
+		conflict []string
+		update   []string
+		query    string
+	}{
+		"postgres": {
+			dialect:  queries.PostgreSQL,
+			conflict: []string{"id"},
+			update:   []string{"name", "age"},
+			query:    "insert into users (id, name, age) values ($1, $2, $3) on conflict (id) do update set name = excluded.name, age = excluded.age",
+		},
+		"postgres do nothing": {
+			dialect:  queries.PostgreSQL,
+			conflict: []string{"id"},
+			query:    "insert into users (id, name, age) values ($1, $2, $3) on conflict (id) do nothing",
+		},
+		"sqlite any conflict": {
+			dialect: queries.SQLite,
+			query:   "insert into users (id, name, age) values (?, ?, ?) on conflict do nothing",
+		},
+		"mysql": {
+			dialect:  queries.MySQL,
+			conflict: []string{"id"},
+			update:   []string{"name", "age"},
+			query:    "insert into users (id, name, age) values (?, ?, ?) on duplicate key update name = values(name), age = values(age)",
+		},
+		"mysql do nothing": {
+			dialect:  queries.MySQL,
+			conflict: []string{"id"},
+			query:    "insert into users (id, name, age) values (?, ?, ?) on duplicate key update id = id",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			qb := queries.Builder{Dialect: tt.dialect}
+			qb.AppendInsert("users", struct {
+				ID   int    `sql:"id"`
+				Name string `sql:"name"`
+				Age  int    `sql:"age"`
+			}{1, "Alice", 30})
+			qb.AppendUpsert(tt.conflict, tt.update)
+			assert.Equal[E](t, qb.String(), tt.query)
+		})
+	}
+
+	qb := queries.Builder{Dialect: queries.PostgreSQL}
+	assert.Panics[E](t, func() { qb.AppendUpsert(nil, []string{"name"}) },
+		"queries: conflict columns must be specified to update the conflicting row")
+}
@@ -0,0 +1,46 @@
+package queries
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"time"
+)
+
+// Pinger is an interface implemented by [sql.DB] and [sql.Conn].
+type Pinger interface {
+	PingContext(ctx context.Context) error
+}
+
+// maxBackoff is the maximum delay between the attempts of [WaitReady], unless the initial one is greater.
+const maxBackoff = 10 * time.Second
+
+// WaitReady pings the database until it responds, e.g. while its container is starting.
+// The delay between the attempts starts at backoff and doubles each time (with jitter), up to 10 seconds.
+// Use a context with a deadline to limit the total wait.
+func WaitReady(ctx context.Context, db Pinger, backoff time.Duration) error {
+	if backoff <= 0 {
+		panic("queries: backoff must be positive")
+	}
+
+	limit := max(backoff, maxBackoff)
+	for {
+		err := db.PingContext(ctx)
+		if err == nil {
+			return nil
+		}
+
+		// the jitter spreads the attempts of several clients starting at the same time.
+		delay := backoff/2 + rand.N(backoff/2+1)
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("waiting for database: %w", errors.Join(ctx.Err(), err))
+		case <-timer.C:
+		}
+
+		backoff = min(backoff*2, limit)
+	}
+}
@@ -0,0 +1,48 @@
+package queries_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go-simpler.org/queries"
+	"go-simpler.org/queries/internal/assert"
+	. "go-simpler.org/queries/internal/assert/EF"
+)
+
+func TestWaitReady(t *testing.T) {
+	ctx := context.Background()
+	errNotReady := errors.New("not ready")
+
+	t.Run("ready", func(t *testing.T) {
+		db := &pinger{failures: 2, err: errNotReady}
+		err := queries.WaitReady(ctx, db, time.Millisecond)
+		assert.NoErr[F](t, err)
+		assert.Equal[E](t, db.attempts, 3)
+	})
+
+	t.Run("deadline exceeded", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+		defer cancel()
+
+		db := &pinger{failures: -1, err: errNotReady}
+		err := queries.WaitReady(ctx, db, time.Millisecond)
+		assert.IsErr[E](t, err, context.DeadlineExceeded)
+		assert.IsErr[E](t, err, errNotReady)
+	})
+}
+
+type pinger struct {
+	failures int // -1 means always.
+	attempts int
+	err      error
+}
+
+func (p *pinger) PingContext(context.Context) error {
+	p.attempts++
+	if p.failures < 0 || p.attempts <= p.failures {
+		return p.err
+	}
+	return nil
+}
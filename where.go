@@ -0,0 +1,87 @@
+package queries
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// AppendfIf calls [Builder.Appendf] only if cond is true.
+// It is useful for optional clauses, e.g. the ones for the filters that are set.
+func (b *Builder) AppendfIf(cond bool, format string, args ...any) {
+	if cond {
+		b.Appendf(format, args...)
+	}
+}
+
+// Where appends the condition to the WHERE clause, formatted as in [Builder.Appendf].
+// The first condition starts the clause (" where ..."), the following ones are joined with AND (" and ...").
+// If no conditions are added, the WHERE keyword is omitted entirely.
+// Conditions with OR must be parenthesized, and the calls must not be interleaved with other appends.
+func (b *Builder) Where(format string, args ...any) {
+	b.startCondition()
+	b.Appendf(format, args...)
+	b.whereEnd = b.query.Len()
+}
+
+// startCondition appends the WHERE keyword for the first condition or AND for the following ones.
+func (b *Builder) startCondition() {
+	if b.whereEnd > 0 && b.whereEnd != b.query.Len() {
+		panic("queries: Where calls must not be interleaved with other appends")
+	}
+	if b.whereEnd == 0 {
+		b.query.WriteString(" where ")
+	} else {
+		b.query.WriteString(" and ")
+	}
+}
+
+// WhereIf calls [Builder.Where] only if cond is true.
+func (b *Builder) WhereIf(cond bool, format string, args ...any) {
+	if cond {
+		b.Where(format, args...)
+	}
+}
+
+// WhereFilter adds a condition to the WHERE clause (see [Builder.Where]) for each field of the filter struct tagged with `sql`
+// that does not have the zero value, e.g. " where name = $1 and age = $2".
+// A pointer field is dereferenced, so it can be used to filter by the zero value.
+// A slice field (except []byte) is rendered as "column in (...)"; an empty one is handled as in [Builder.Appendf].
+func (b *Builder) WhereFilter(filter any) {
+	v := reflect.Indirect(reflect.ValueOf(filter))
+	if v.Kind() != reflect.Struct {
+		panic("queries: filter must be a struct or a pointer to a struct")
+	}
+
+	for _, f := range parseStruct(v.Type()) {
+		fv := v.Field(f.index)
+		if fv.IsZero() {
+			continue
+		}
+
+		verb := b.verb()
+		b.startCondition()
+		switch {
+		case fv.Kind() == reflect.Pointer:
+			fmt.Fprintf(&b.query, "%s = %s", f.column, b.bind(verb, fv.Elem().Interface()))
+		case fv.Kind() == reflect.Slice && fv.Type().Elem().Kind() != reflect.Uint8 && !f.json:
+			if fv.Len() == 0 {
+				if !b.EmptyAsNull {
+					panic(fmt.Sprintf("queries: %s field must not be an empty slice", v.Type().Field(f.index).Name))
+				}
+				fmt.Fprintf(&b.query, "%s in (null)", f.column)
+				break
+			}
+			fmt.Fprintf(&b.query, "%s in (", f.column)
+			for i := 0; i < fv.Len(); i++ {
+				if i > 0 {
+					b.query.WriteString(", ")
+				}
+				b.query.WriteString(b.bind(verb, fv.Index(i).Interface()))
+			}
+			b.query.WriteString(")")
+		default:
+			fmt.Fprintf(&b.query, "%s = %s", f.column, b.bind(verb, f.value(v)))
+		}
+		b.whereEnd = b.query.Len()
+	}
+}
@@ -0,0 +1,112 @@
+package queries_test
+
+import (
+	"testing"
+
+	"go-simpler.org/queries"
+	"go-simpler.org/queries/internal/assert"
+	. "go-simpler.org/queries/internal/assert/EF"
+)
+
+func TestBuilder_Where(t *testing.T) {
+	type filter struct {
+		name   string
+		minAge int
+	}
+
+	tests := map[string]struct {
+		filter filter
+		query  string
+		args   []any
+	}{
+		"no conditions": {
+			filter: filter{},
+			query:  "select * from users order by id",
+			args:   nil,
+		},
+		"one condition": {
+			filter: filter{name: "Alice"},
+			query:  "select * from users where name = $1 order by id",
+			args:   []any{"Alice"},
+		},
+		"all conditions": {
+			filter: filter{name: "Alice", minAge: 18},
+			query:  "select * from users where name = $1 and age >= $2 order by id",
+			args:   []any{"Alice", 18},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var qb queries.Builder
+			qb.Appendf("select * from users")
+			qb.WhereIf(tt.filter.name != "", "name = %$", tt.filter.name)
+			qb.WhereIf(tt.filter.minAge > 0, "age >= %$", tt.filter.minAge)
+			qb.Appendf(" order by id")
+			assert.Equal[E](t, qb.String(), tt.query)
+			assert.Equal[E](t, qb.Args, tt.args)
+		})
+	}
+
+	t.Run("interleaved", func(t *testing.T) {
+		var qb queries.Builder
+		qb.Where("id = %$", 1)
+		qb.Appendf(" order by id")
+		assert.Panics[E](t, func() { qb.Where("name = %$", "Alice") }, "queries: Where calls must not be interleaved with other appends")
+	})
+}
+
+func TestBuilder_AppendfIf(t *testing.T) {
+	var qb queries.Builder
+	qb.Appendf("select * from users")
+	qb.AppendfIf(false, " where name = %$", "Alice")
+	qb.AppendfIf(true, " limit %$", 10)
+	assert.Equal[E](t, qb.String(), "select * from users limit $1")
+	assert.Equal[E](t, qb.Args, []any{10})
+}
+
+func TestBuilder_WhereFilter(t *testing.T) {
+	type filter struct {
+		Name   string `sql:"name"`
+		Active *bool  `sql:"active"`
+		IDs    []int  `sql:"id"`
+		Age    int
+	}
+
+	active := false
+
+	tests := map[string]struct {
+		filter filter
+		query  string
+		args   []any
+	}{
+		"empty": {
+			filter: filter{Age: 18},
+			query:  "select * from users",
+			args:   nil,
+		},
+		"all fields": {
+			filter: filter{Name: "Alice", Active: &active, IDs: []int{1, 2}},
+			query:  "select * from users where name = ? and active = ? and id in (?, ?)",
+			args:   []any{"Alice", false, 1, 2},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			qb := queries.Builder{Dialect: queries.MySQL}
+			qb.Appendf("select * from users")
+			qb.WhereFilter(tt.filter)
+			assert.Equal[E](t, qb.String(), tt.query)
+			assert.Equal[E](t, qb.Args, tt.args)
+		})
+	}
+
+	t.Run("with Where", func(t *testing.T) {
+		qb := queries.Builder{Dialect: queries.PostgreSQL}
+		qb.Appendf("select * from users")
+		qb.Where("deleted_at is null")
+		qb.WhereFilter(&filter{Name: "Alice"})
+		assert.Equal[E](t, qb.String(), "select * from users where deleted_at is null and name = $1")
+	})
+}
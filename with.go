@@ -0,0 +1,28 @@
+package queries
+
+import "fmt"
+
+// With appends the named common table expression (CTE) to the WITH clause, e.g. "with a as (...), b as (...)".
+// The first call must start the query, and the following ones must come right after it;
+// the placeholders of each CTE are renumbered as if it was embedded with %s (see [Builder.Appendf]).
+// The main query is appended after the clause as usual:
+//
+//	qb.With("recent", recent)
+//	qb.Appendf(" select * from recent where total > %$", 100)
+func (b *Builder) With(name string, cte *Builder) {
+	if !isPlainIdent(name) {
+		panic(fmt.Sprintf("queries: bad CTE name %q", name))
+	}
+
+	switch {
+	case b.query.Len() == 0:
+		b.query.WriteString("with ")
+	case b.withEnd == b.query.Len():
+		b.query.WriteString(", ")
+	default:
+		panic("queries: With must be called before other appends")
+	}
+
+	fmt.Fprintf(&b.query, "%s as (%s)", name, b.embed(cte))
+	b.withEnd = b.query.Len()
+}
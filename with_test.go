@@ -0,0 +1,29 @@
+package queries_test
+
+import (
+	"testing"
+
+	"go-simpler.org/queries"
+	"go-simpler.org/queries/internal/assert"
+	. "go-simpler.org/queries/internal/assert/EF"
+)
+
+func TestBuilder_With(t *testing.T) {
+	var paid queries.Builder
+	paid.Appendf("select user_id, sum(total) as total from orders where status = %$ group by user_id", "paid")
+
+	var active queries.Builder
+	active.Appendf("select id from users where last_seen > %$", "2024-01-01")
+
+	var qb queries.Builder
+	qb.With("paid", &paid)
+	qb.With("active", &active)
+	qb.Appendf(" select * from paid join active on paid.user_id = active.id where total > %$", 100)
+
+	assert.Equal[E](t, qb.String(), "with paid as (select user_id, sum(total) as total from orders where status = $1 group by user_id), "+
+		"active as (select id from users where last_seen > $2) select * from paid join active on paid.user_id = active.id where total > $3")
+	assert.Equal[E](t, qb.Args, []any{"paid", "2024-01-01", 100})
+
+	assert.Panics[E](t, func() { qb.With("late", &active) }, "queries: With must be called before other appends")
+	assert.Panics[E](t, func() { new(queries.Builder).With("bad name", &active) }, `queries: bad CTE name "bad name"`)
+}